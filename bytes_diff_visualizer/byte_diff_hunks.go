@@ -0,0 +1,216 @@
+package bytes_diff_visualizer
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/make-core/tester-utils/internal/myers"
+)
+
+// maxHunkCount is the number of differing regions shown by VisualizeByteDiffHunks, the rest are summarized in a
+// single line.
+const maxHunkCount = 5
+
+// byteDiffHunk is a group of nearby changes, along with the surrounding context to display.
+type byteDiffHunk struct {
+	// Ranges to display (changes + context)
+	expectedStart int
+	expectedEnd   int
+	actualStart   int
+	actualEnd     int
+
+	// Ranges of the changes alone
+	changedExpectedStart int
+	changedExpectedEnd   int
+	changedActualStart   int
+	changedActualEnd     int
+
+	// Bytes that are present in one slice but not the other
+	expectedOnly []bool
+	actualOnly   []bool
+}
+
+// VisualizeByteDiffHunks visualizes every region where actual differs from expected, returning lines to be presented
+// to the user.
+//
+// Unlike VisualizeByteDiff (which only shows a window around the first differing byte), this uses an edit-distance
+// algorithm to align the two slices, so an inserted or missing byte doesn't make everything after it look different.
+// Each region is shown with contextByteCount bytes of unchanged context on either side. Regions that are separated by
+// at most 2*contextByteCount unchanged bytes are merged. Only the first few regions are shown, and long regions are
+// truncated.
+func VisualizeByteDiffHunks(actual []byte, expected []byte, contextByteCount int) []string {
+	return VisualizeByteDiffHunksWithOptions(actual, expected, contextByteCount, Options{})
+}
+
+// VisualizeByteDiffHunksWithOptions is like VisualizeByteDiffHunks, but with a configurable layout. Each side of a
+// region is truncated to Options.WindowByteCount bytes. Sides are always stacked, Options.Layout is ignored.
+func VisualizeByteDiffHunksWithOptions(actual []byte, expected []byte, contextByteCount int, options Options) []string {
+	if bytes.Equal(actual, expected) {
+		return []string{}
+	}
+
+	options = options.withDefaults()
+	hunks := computeByteDiffHunks(actual, expected, contextByteCount)
+
+	linesBuffer := bytes.NewBuffer([]byte{})
+
+	fmt.Fprintf(linesBuffer, "Expected %d bytes, got %d bytes. Found %s.\n", len(expected), len(actual), pluralize(len(hunks), "differing region", "differing regions"))
+
+	for hunkIndex, hunk := range hunks[:intmin(len(hunks), maxHunkCount)] {
+		linesBuffer.Write([]byte("\n"))
+
+		fmt.Fprintf(
+			linesBuffer,
+			"Region %d of %d (expected bytes %v-%v, actual bytes %v-%v):\n",
+			hunkIndex+1,
+			len(hunks),
+			hunk.changedExpectedStart,
+			hunk.changedExpectedEnd,
+			hunk.changedActualStart,
+			hunk.changedActualEnd,
+		)
+
+		expectedHeader := fmt.Sprintf("Expected (bytes %v-%v), hexadecimal:", hunk.expectedStart, hunk.expectedEnd)
+		actualHeader := fmt.Sprintf("Actual (bytes %v-%v), hexadecimal:", hunk.actualStart, hunk.actualEnd)
+
+		// The hex column fits both a full row (3 characters per byte) and the headers
+		layout := byteDiffLayout{
+			options:        options,
+			hexColumnWidth: intmax(options.BytesPerRow*3, intmax(len(expectedHeader), len(actualHeader))+1),
+		}
+
+		layout.writeHunkSide(linesBuffer, expectedHeader, expected, hunk.expectedStart, hunk.expectedEnd, hunk.expectedOnly, color.FgHiGreen)
+		linesBuffer.Write([]byte("\n"))
+		layout.writeHunkSide(linesBuffer, actualHeader, actual, hunk.actualStart, hunk.actualEnd, hunk.actualOnly, color.FgHiRed)
+	}
+
+	if hiddenHunkCount := len(hunks) - maxHunkCount; hiddenHunkCount > 0 {
+		linesBuffer.Write([]byte("\n"))
+		fmt.Fprintf(linesBuffer, "... %s not shown.\n", pluralize(hiddenHunkCount, "more differing region", "more differing regions"))
+	}
+
+	output := linesBuffer.String()
+	if output[len(output)-1] == '\n' {
+		output = output[:len(output)-1]
+	}
+	return strings.Split(output, "\n")
+}
+
+func computeByteDiffHunks(actual []byte, expected []byte, contextByteCount int) []byteDiffHunk {
+	contextByteCount = intmax(contextByteCount, 0)
+
+	expectedOnly := make([]bool, len(expected))
+	actualOnly := make([]bool, len(actual))
+
+	// Each change is a run of non-equal operations: [expectedStart, expectedEnd, actualStart, actualEnd]
+	changes := [][4]int{}
+
+	for _, operation := range myers.Diff(expected, actual) {
+		if operation.Type == myers.Equal {
+			continue
+		}
+
+		for i := operation.AStart; i < operation.AEnd; i++ {
+			expectedOnly[i] = true
+		}
+
+		for i := operation.BStart; i < operation.BEnd; i++ {
+			actualOnly[i] = true
+		}
+
+		if len(changes) > 0 {
+			last := &changes[len(changes)-1]
+			if last[1] == operation.AStart && last[3] == operation.BStart {
+				last[1] = operation.AEnd
+				last[3] = operation.BEnd
+				continue
+			}
+		}
+
+		changes = append(changes, [4]int{operation.AStart, operation.AEnd, operation.BStart, operation.BEnd})
+	}
+
+	hunks := []byteDiffHunk{}
+
+	for _, change := range changes {
+		if len(hunks) > 0 {
+			last := &hunks[len(hunks)-1]
+
+			// Equal bytes between changes are the same count on both sides, so checking one side is enough
+			if change[0]-last.changedExpectedEnd <= 2*contextByteCount {
+				last.changedExpectedEnd = change[1]
+				last.changedActualEnd = change[3]
+				continue
+			}
+		}
+
+		hunks = append(hunks, byteDiffHunk{
+			changedExpectedStart: change[0],
+			changedExpectedEnd:   change[1],
+			changedActualStart:   change[2],
+			changedActualEnd:     change[3],
+			expectedOnly:         expectedOnly,
+			actualOnly:           actualOnly,
+		})
+	}
+
+	for i := range hunks {
+		hunk := &hunks[i]
+		hunk.expectedStart = intmax(0, hunk.changedExpectedStart-contextByteCount)
+		hunk.expectedEnd = intmin(len(expected), hunk.changedExpectedEnd+contextByteCount)
+		hunk.actualStart = intmax(0, hunk.changedActualStart-contextByteCount)
+		hunk.actualEnd = intmin(len(actual), hunk.changedActualEnd+contextByteCount)
+	}
+
+	return hunks
+}
+
+func (l byteDiffLayout) writeHunkSide(linesBuffer *bytes.Buffer, header string, value []byte, start int, end int, isChanged []bool, chosenColor color.Attribute) {
+	fmt.Fprintf(linesBuffer, "%s%s| ASCII:\n", l.formatBlankGutter(), PadRight(header, " ", l.hexColumnWidth))
+
+	if start == end {
+		fmt.Fprintf(linesBuffer, "%s%s|\n", l.formatBlankGutter(), PadRight("(no bytes)", " ", l.hexColumnWidth))
+		return
+	}
+
+	shownEnd := intmin(end, start+l.options.WindowByteCount)
+
+	for i := start; i < shownEnd; i += l.options.BytesPerRow {
+		lineEnd := intmin(i+l.options.BytesPerRow, shownEnd)
+
+		hexRepresentations := []string{}
+		asciiRepresentations := []string{}
+
+		for j := i; j < lineEnd; j++ {
+			hex := formatBytesAsHex(value[j : j+1])
+			ascii := formatBytesAsAscii(value[j : j+1])
+
+			if isChanged[j] {
+				hex = colorizeString(chosenColor, hex)
+				ascii = colorizeString(chosenColor, ascii)
+			}
+
+			hexRepresentations = append(hexRepresentations, hex)
+			asciiRepresentations = append(asciiRepresentations, ascii)
+		}
+
+		bytesAsHex := padRightIgnoringANSIEscapeCodes(strings.Join(hexRepresentations, " "), l.hexColumnWidth)
+
+		fmt.Fprintf(linesBuffer, "%s%v| %v\n", l.formatGutter(i), bytesAsHex, strings.Join(asciiRepresentations, ""))
+	}
+
+	if hiddenByteCount := end - shownEnd; hiddenByteCount > 0 {
+		hiddenBytesNote := fmt.Sprintf("... %s not shown", pluralize(hiddenByteCount, "more byte", "more bytes"))
+		fmt.Fprintf(linesBuffer, "%s%s|\n", l.formatBlankGutter(), PadRight(hiddenBytesNote, " ", l.hexColumnWidth))
+	}
+}
+
+func pluralize(count int, singular string, plural string) string {
+	if count == 1 {
+		return fmt.Sprintf("%d %s", count, singular)
+	}
+
+	return fmt.Sprintf("%d %s", count, plural)
+}
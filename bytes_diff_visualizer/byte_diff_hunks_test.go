@@ -0,0 +1,149 @@
+package bytes_diff_visualizer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVisualizeByteDiffHunksIdentical(t *testing.T) {
+	assert.Equal(t, []string{}, VisualizeByteDiffHunks([]byte("abc"), []byte("abc"), 4))
+}
+
+func TestVisualizeByteDiffHunksAlignsInsertions(t *testing.T) {
+	actual := []byte("blob\000header")
+	expected := []byte("blob\000\000header") // Has an extra null byte
+
+	result := VisualizeByteDiffHunks(actual, expected, 3)
+
+	expectedLines := []string{
+		"Expected 12 bytes, got 11 bytes. Found 1 differing region.",
+		"",
+		"Region 1 of 1 (expected bytes 5-6, actual bytes 5-5):",
+		"Expected (bytes 2-9), hexadecimal:                          | ASCII:",
+		"6f 62 00 00 68 65 61                                        | ob..hea",
+		"",
+		"Actual (bytes 2-8), hexadecimal:                            | ASCII:",
+		"6f 62 00 68 65 61                                           | ob.hea",
+	}
+
+	assert.Equal(t, expectedLines, mapStrings(result, stripANSI))
+
+	// Only the extra null byte should be highlighted
+	assert.Equal(t, "6f 62 00 "+colorizeString(color.FgHiGreen, "00")+" 68 65 61"+strings.Repeat(" ", 40)+"| ob."+colorizeString(color.FgHiGreen, ".")+"hea", result[4])
+	assert.Equal(t, "6f 62 00 68 65 61"+strings.Repeat(" ", 43)+"| ob.hea", result[7])
+}
+
+func TestVisualizeByteDiffHunksShowsAllRegions(t *testing.T) {
+	expected := []byte("id=0001;name=alice;role=admin;active=true")
+	actual := []byte("id=0001;name=alicf;role=admin;active=false")
+
+	result := VisualizeByteDiffHunks(actual, expected, 2)
+
+	expectedLines := []string{
+		"Expected 41 bytes, got 42 bytes. Found 2 differing regions.",
+		"",
+		"Region 1 of 2 (expected bytes 17-18, actual bytes 17-18):",
+		"Expected (bytes 15-20), hexadecimal:                        | ASCII:",
+		"69 63 65 3b 72                                              | ice;r",
+		"",
+		"Actual (bytes 15-20), hexadecimal:                          | ASCII:",
+		"69 63 66 3b 72                                              | icf;r",
+		"",
+		"Region 2 of 2 (expected bytes 37-40, actual bytes 37-41):",
+		"Expected (bytes 35-41), hexadecimal:                        | ASCII:",
+		"65 3d 74 72 75 65                                           | e=true",
+		"",
+		"Actual (bytes 35-42), hexadecimal:                          | ASCII:",
+		"65 3d 66 61 6c 73 65                                        | e=false",
+	}
+
+	assert.Equal(t, expectedLines, mapStrings(result, stripANSI))
+}
+
+func TestVisualizeByteDiffHunksMergesNearbyRegions(t *testing.T) {
+	result := VisualizeByteDiffHunks([]byte("aXcdeYg"), []byte("abcdefg"), 2)
+
+	assert.Equal(t, "Expected 7 bytes, got 7 bytes. Found 1 differing region.", stripANSI(result[0]))
+	assert.Equal(t, "Region 1 of 1 (expected bytes 1-6, actual bytes 1-6):", stripANSI(result[2]))
+}
+
+func TestVisualizeByteDiffHunksWithEmptySide(t *testing.T) {
+	result := VisualizeByteDiffHunks([]byte("abc"), []byte{}, 2)
+
+	expectedLines := []string{
+		"Expected 0 bytes, got 3 bytes. Found 1 differing region.",
+		"",
+		"Region 1 of 1 (expected bytes 0-0, actual bytes 0-3):",
+		"Expected (bytes 0-0), hexadecimal:                          | ASCII:",
+		"(no bytes)                                                  |",
+		"",
+		"Actual (bytes 0-3), hexadecimal:                            | ASCII:",
+		"61 62 63                                                    | abc",
+	}
+
+	assert.Equal(t, expectedLines, mapStrings(result, stripANSI))
+}
+
+func TestVisualizeByteDiffHunksLimitsRegionCount(t *testing.T) {
+	expected := []byte(strings.Repeat("a----------", 8))
+	actual := []byte(strings.Repeat("b----------", 8))
+
+	result := mapStrings(VisualizeByteDiffHunks(actual, expected, 2), stripANSI)
+
+	assert.Equal(t, "Expected 88 bytes, got 88 bytes. Found 8 differing regions.", result[0])
+	assert.Contains(t, result, "Region 5 of 8 (expected bytes 44-45, actual bytes 44-45):")
+	assert.NotContains(t, result, "Region 6 of 8 (expected bytes 55-56, actual bytes 55-56):")
+	assert.Equal(t, []string{"", "... 3 more differing regions not shown."}, result[len(result)-2:])
+}
+
+func TestVisualizeByteDiffHunksTruncatesLongRegions(t *testing.T) {
+	expected := []byte(strings.Repeat("a", 250))
+	actual := []byte(strings.Repeat("b", 250))
+
+	result := mapStrings(VisualizeByteDiffHunks(actual, expected, 2), stripANSI)
+
+	assert.Equal(t, []string{
+		"Expected 250 bytes, got 250 bytes. Found 1 differing region.",
+		"",
+		"Region 1 of 1 (expected bytes 0-250, actual bytes 0-250):",
+		"Expected (bytes 0-250), hexadecimal:                        | ASCII:",
+	}, result[:4])
+
+	// 100 bytes are shown, 20 per row
+	assert.Equal(t, "... 150 more bytes not shown                                |", result[9])
+	assert.Len(t, result, 2*7+4)
+}
+
+func TestVisualizeByteDiffHunksWithOptions(t *testing.T) {
+	expected := []byte("0123456789abcdef")
+	actual := []byte("0123456789abcdeX")
+
+	result := VisualizeByteDiffHunksWithOptions(actual, expected, 6, Options{BytesPerRow: 4, WindowByteCount: 5, ShouldShowOffsets: true})
+
+	assert.Equal(t, []string{
+		"Expected 16 bytes, got 16 bytes. Found 1 differing region.",
+		"",
+		"Region 1 of 1 (expected bytes 15-16, actual bytes 15-16):",
+		"          Expected (bytes 9-16), hexadecimal: | ASCII:",
+		"00000009  39 61 62 63                         | 9abc",
+		"0000000d  64                                  | d",
+		"          ... 2 more bytes not shown          |",
+		"",
+		"          Actual (bytes 9-16), hexadecimal:   | ASCII:",
+		"00000009  39 61 62 63                         | 9abc",
+		"0000000d  64                                  | d",
+		"          ... 2 more bytes not shown          |",
+	}, mapStrings(result, stripANSI))
+}
+
+func mapStrings(values []string, fn func(string) string) []string {
+	result := []string{}
+	for _, value := range values {
+		result = append(result, fn(value))
+	}
+
+	return result
+}
@@ -3,20 +3,85 @@ package bytes_diff_visualizer
 import (
 	"bytes"
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/fatih/color"
 )
 
+type OffsetFormat string
+
+const (
+	OffsetFormatHex     OffsetFormat = "hex"
+	OffsetFormatDecimal OffsetFormat = "decimal"
+)
+
+type Layout string
+
+const (
+	// LayoutStacked renders expected bytes above actual bytes
+	LayoutStacked Layout = "stacked"
+
+	// LayoutSideBySide renders expected and actual bytes next to each other, row by row
+	LayoutSideBySide Layout = "side-by-side"
+)
+
+// Options control how VisualizeByteDiffWithOptions and VisualizeByteDiffHunksWithOptions render a diff. Zero values
+// fall back to the defaults used by VisualizeByteDiff.
+type Options struct {
+	// WindowByteCount is the number of bytes shown around the first differing byte (or for each side of a region, when
+	// visualizing hunks). Defaults to 100.
+	WindowByteCount int
+
+	// BytesPerRow is the number of bytes shown per row. Defaults to 20.
+	BytesPerRow int
+
+	// ShouldShowOffsets adds a gutter on the left with the offset of the first byte in each row
+	ShouldShowOffsets bool
+
+	// OffsetFormat controls how offsets in the gutter are formatted. Defaults to OffsetFormatHex.
+	OffsetFormat OffsetFormat
+
+	// Layout defaults to LayoutStacked
+	Layout Layout
+}
+
+func (o Options) withDefaults() Options {
+	if o.WindowByteCount <= 0 {
+		o.WindowByteCount = 100
+	}
+
+	if o.BytesPerRow <= 0 {
+		o.BytesPerRow = 20
+	}
+
+	if o.OffsetFormat == "" {
+		o.OffsetFormat = OffsetFormatHex
+	}
+
+	if o.Layout == "" {
+		o.Layout = LayoutStacked
+	}
+
+	return o
+}
+
 // VisualizeByteDiff visualizes the difference between two byte slices, returning lines to be presented to the user.
 //
-// The lines will include ANSI escape codes to colorize the output.
+// The lines will include ANSI escape codes to colorize the output, unless colors are disabled (see color_policy).
 func VisualizeByteDiff(actual []byte, expected []byte) []string {
+	return VisualizeByteDiffWithOptions(actual, expected, Options{})
+}
+
+// VisualizeByteDiffWithOptions is like VisualizeByteDiff, but with a configurable layout.
+func VisualizeByteDiffWithOptions(actual []byte, expected []byte, options Options) []string {
 	// If both are exactly the same, return an empty slice
 	if bytes.Equal(actual, expected) {
 		return []string{}
 	}
 
+	options = options.withDefaults()
+
 	// Find the index of the first differing byte
 	var firstDiffIndex int = -1
 
@@ -36,44 +101,149 @@ func VisualizeByteDiff(actual []byte, expected []byte) []string {
 		}
 	}
 
-	totalByteCountToDisplay := 100
-	byteCountPerLine := 20
+	totalByteCountToDisplay := options.WindowByteCount
+	byteCountPerLine := options.BytesPerRow
 	byteRangeStart := intmax(0, firstDiffIndex-(totalByteCountToDisplay/2))
 	byteRangeEnd := intmin(byteRangeStart+totalByteCountToDisplay, intmax(len(actual), len(expected)))
 
+	expectedSide := byteDiffSide{
+		header:      fmt.Sprintf("Expected (bytes %v-%v), hexadecimal:", byteRangeStart, byteRangeEnd),
+		value:       expected,
+		chosenColor: color.FgHiGreen,
+	}
+
+	actualSide := byteDiffSide{
+		header:      fmt.Sprintf("Actual (bytes %v-%v), hexadecimal:", byteRangeStart, byteRangeEnd),
+		value:       actual,
+		chosenColor: color.FgHiRed,
+	}
+
+	// The hex column fits both a full row (3 characters per byte) and the headers
+	hexColumnWidth := intmax(byteCountPerLine*3, intmax(len(expectedSide.header), len(actualSide.header))+1)
+
+	layout := byteDiffLayout{
+		options:        options,
+		byteRangeStart: byteRangeStart,
+		byteRangeEnd:   byteRangeEnd,
+		firstDiffIndex: firstDiffIndex,
+		hexColumnWidth: hexColumnWidth,
+	}
+
 	linesBuffer := bytes.NewBuffer([]byte{})
 
-	leftHeader := PadRight(fmt.Sprintf("Expected (bytes %v-%v), hexadecimal:", byteRangeStart, byteRangeEnd), " ", 60)
-	fmt.Fprintf(linesBuffer, "%s| ASCII:\n", leftHeader)
+	if options.Layout == LayoutSideBySide {
+		layout.writeSideBySide(linesBuffer, expectedSide, actualSide)
+	} else {
+		layout.writeSide(linesBuffer, expectedSide)
+		linesBuffer.Write([]byte("\n"))
+		layout.writeSide(linesBuffer, actualSide)
+	}
 
-	for i := byteRangeStart; i < intmin(byteRangeEnd, len(expected)); i += byteCountPerLine {
-		end := intmin(i+byteCountPerLine, len(expected))
+	output := linesBuffer.String()
+	if output[len(output)-1] == '\n' {
+		output = output[:len(output)-1]
+	}
+	return strings.Split(output, "\n")
+}
+
+type byteDiffSide struct {
+	header      string
+	value       []byte
+	chosenColor color.Attribute
+}
+
+type byteDiffLayout struct {
+	options        Options
+	byteRangeStart int
+	byteRangeEnd   int
+	firstDiffIndex int
+	hexColumnWidth int
+}
 
-		bytesAsHex := formatHexWithColorizedByte(expected, i, firstDiffIndex, end, color.FgHiGreen)
-		bytesAsAscii := formatAsciiWithColorizedByte(expected, i, firstDiffIndex, end, color.FgHiGreen)
+// rowStarts returns the offset of the first byte in each row that is shown for value
+func (l byteDiffLayout) rowStarts(value []byte) []int {
+	starts := []int{}
 
-		fmt.Fprintf(linesBuffer, "%v| %v\n", bytesAsHex, bytesAsAscii)
+	for i := l.byteRangeStart; i < intmin(l.byteRangeEnd, len(value)); i += l.options.BytesPerRow {
+		starts = append(starts, i)
 	}
 
-	linesBuffer.Write([]byte("\n"))
+	return starts
+}
 
-	leftHeader = PadRight(fmt.Sprintf("Actual (bytes %v-%v), hexadecimal:", byteRangeStart, byteRangeEnd), " ", 60)
-	fmt.Fprintf(linesBuffer, "%s| ASCII:\n", leftHeader)
+// formatRow returns the hex & ASCII columns for the row of value starting at i
+func (l byteDiffLayout) formatRow(side byteDiffSide, i int) (string, string) {
+	end := intmin(i+l.options.BytesPerRow, len(side.value))
 
-	for i := byteRangeStart; i < intmin(byteRangeEnd, len(actual)); i += byteCountPerLine {
-		end := intmin(i+byteCountPerLine, len(actual))
+	bytesAsHex := formatHexWithColorizedByte(side.value, i, l.firstDiffIndex, end, side.chosenColor, l.hexColumnWidth)
+	bytesAsAscii := formatAsciiWithColorizedByte(side.value, i, l.firstDiffIndex, end, side.chosenColor)
 
-		bytesAsHex := formatHexWithColorizedByte(actual, i, firstDiffIndex, end, color.FgHiRed)
-		bytesAsAscii := formatAsciiWithColorizedByte(actual, i, firstDiffIndex, end, color.FgHiRed)
+	return bytesAsHex, bytesAsAscii
+}
 
-		fmt.Fprintf(linesBuffer, "%v| %v\n", bytesAsHex, bytesAsAscii)
+func (l byteDiffLayout) formatGutter(offset int) string {
+	if !l.options.ShouldShowOffsets {
+		return ""
 	}
 
-	output := linesBuffer.String()
-	if output[len(output)-1] == '\n' {
-		output = output[:len(output)-1]
+	if l.options.OffsetFormat == OffsetFormatDecimal {
+		return fmt.Sprintf("%8d  ", offset)
+	}
+
+	return fmt.Sprintf("%08x  ", offset)
+}
+
+func (l byteDiffLayout) formatBlankGutter() string {
+	if !l.options.ShouldShowOffsets {
+		return ""
+	}
+
+	return strings.Repeat(" ", 10)
+}
+
+func (l byteDiffLayout) writeSide(linesBuffer *bytes.Buffer, side byteDiffSide) {
+	leftHeader := PadRight(side.header, " ", l.hexColumnWidth)
+	fmt.Fprintf(linesBuffer, "%s%s| ASCII:\n", l.formatBlankGutter(), leftHeader)
+
+	for _, i := range l.rowStarts(side.value) {
+		bytesAsHex, bytesAsAscii := l.formatRow(side, i)
+		fmt.Fprintf(linesBuffer, "%s%v| %v\n", l.formatGutter(i), bytesAsHex, bytesAsAscii)
+	}
+}
+
+func (l byteDiffLayout) writeSideBySide(linesBuffer *bytes.Buffer, expectedSide byteDiffSide, actualSide byteDiffSide) {
+	// The ASCII column of the left side is padded so that the right side lines up
+	asciiColumnWidth := intmax(len("ASCII:"), l.options.BytesPerRow)
+
+	fmt.Fprintf(
+		linesBuffer,
+		"%s%s| %s || %s| ASCII:\n",
+		l.formatBlankGutter(),
+		PadRight(expectedSide.header, " ", l.hexColumnWidth),
+		PadRight("ASCII:", " ", asciiColumnWidth),
+		PadRight(actualSide.header, " ", l.hexColumnWidth),
+	)
+
+	expectedRowStarts := l.rowStarts(expectedSide.value)
+	actualRowStarts := l.rowStarts(actualSide.value)
+
+	for rowIndex := 0; rowIndex < intmax(len(expectedRowStarts), len(actualRowStarts)); rowIndex++ {
+		i := l.byteRangeStart + rowIndex*l.options.BytesPerRow
+
+		expectedHex, expectedAscii := strings.Repeat(" ", l.hexColumnWidth), ""
+		if rowIndex < len(expectedRowStarts) {
+			expectedHex, expectedAscii = l.formatRow(expectedSide, i)
+		}
+
+		actualHex, actualAscii := strings.Repeat(" ", l.hexColumnWidth), ""
+		if rowIndex < len(actualRowStarts) {
+			actualHex, actualAscii = l.formatRow(actualSide, i)
+		}
+
+		expectedAscii = padRightIgnoringANSIEscapeCodes(expectedAscii, asciiColumnWidth)
+
+		fmt.Fprintf(linesBuffer, "%s%v| %v || %v| %v\n", l.formatGutter(i), expectedHex, expectedAscii, actualHex, actualAscii)
 	}
-	return strings.Split(output, "\n")
 }
 
 func formatBytesAsAscii(value []byte) string {
@@ -91,11 +261,11 @@ func formatBytesAsAscii(value []byte) string {
 	return strings.Join(asciiRepresentations, "")
 }
 
-func formatHexWithColorizedByte(value []byte, i int, firstDiffIndex int, end int, chosenColor color.Attribute) string {
+func formatHexWithColorizedByte(value []byte, i int, firstDiffIndex int, end int, chosenColor color.Attribute, width int) string {
 	if firstDiffIndex >= i && firstDiffIndex < end {
-		return PadRight(formatHexWithColorizedByteHelper(value, i, firstDiffIndex, end, chosenColor), " ", 69)
+		return padRightIgnoringANSIEscapeCodes(formatHexWithColorizedByteHelper(value, i, firstDiffIndex, end, chosenColor), width)
 	} else {
-		return PadRight(formatBytesAsHex(value[i:end]), " ", 60)
+		return PadRight(formatBytesAsHex(value[i:end]), " ", width)
 	}
 }
 
@@ -139,6 +309,18 @@ func intmin(a int, b int) int {
 	return b
 }
 
+var ansiEscapeCodeRegex = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func removeANSIEscapeCodes(value string) string {
+	return ansiEscapeCodeRegex.ReplaceAllString(value, "")
+}
+
+// padRightIgnoringANSIEscapeCodes pads str with spaces to length. ANSI escape codes (if any) don't take up space in
+// the terminal, so they don't count towards the length.
+func padRightIgnoringANSIEscapeCodes(str string, length int) string {
+	return PadRight(str, " ", length+len(str)-len(removeANSIEscapeCodes(str)))
+}
+
 func colorizeString(colorToUse color.Attribute, msg string) string {
 	c := color.New(colorToUse)
 	return c.Sprint(msg)
@@ -145,6 +145,27 @@ func TestVisualizeByteDiffWorksWithColoredOutput(t *testing.T) {
 	assert.Equal(t, len(expectedLines), len(result))
 }
 
+func TestVisualizeByteDiffWorksWithColorsDisabled(t *testing.T) {
+	originalNoColor := color.NoColor
+	defer func() { color.NoColor = originalNoColor }()
+	color.NoColor = true
+
+	actual := []byte("Hello, World!")
+	expected := []byte("Hello, Go!")
+
+	result := VisualizeByteDiff(actual, expected)
+
+	expectedLines := []string{
+		"Expected (bytes 0-13), hexadecimal:                         | ASCII:",
+		"48 65 6c 6c 6f 2c 20 47 6f 21                               | Hello, Go!",
+		"",
+		"Actual (bytes 0-13), hexadecimal:                           | ASCII:",
+		"48 65 6c 6c 6f 2c 20 57 6f 72 6c 64 21                      | Hello, World!",
+	}
+
+	assert.Equal(t, expectedLines, result)
+}
+
 func stripANSI(data string) string {
 	// https://github.com/acarl005/stripansi/blob/master/stripansi.go
 	const ansi = "[\u001B\u009B][[\\]()#;?]*(?:(?:(?:[a-zA-Z\\d]*(?:;[a-zA-Z\\d]*)*)?\u0007)|(?:(?:\\d{1,4}(?:;\\d{0,4})*)?[\\dA-PRZcf-ntqry=><~]))"
@@ -153,3 +174,60 @@ func stripANSI(data string) string {
 
 	return string(re.ReplaceAll([]byte(data), []byte("")))
 }
+
+func TestVisualizeByteDiffWithOptionsDefaultsMatchVisualizeByteDiff(t *testing.T) {
+	actual := []byte("1234567890123457890123457890efgh")
+	expected := []byte("1234567890123457890123457890abcd")
+
+	assert.Equal(t, VisualizeByteDiff(actual, expected), VisualizeByteDiffWithOptions(actual, expected, Options{}))
+}
+
+func TestVisualizeByteDiffWithOffsetsAndCustomRows(t *testing.T) {
+	actual := []byte("0123456789abcdefXhijk")
+	expected := []byte("0123456789abcdefghijk")
+
+	result := VisualizeByteDiffWithOptions(actual, expected, Options{
+		WindowByteCount:   12,
+		BytesPerRow:       8,
+		ShouldShowOffsets: true,
+	})
+
+	expectedLines := []string{
+		"          Expected (bytes 10-21), hexadecimal: | ASCII:",
+		"0000000a  61 62 63 64 65 66 67 68              | abcdefgh",
+		"00000012  69 6a 6b                             | ijk",
+		"",
+		"          Actual (bytes 10-21), hexadecimal:   | ASCII:",
+		"0000000a  61 62 63 64 65 66 58 68              | abcdefXh",
+		"00000012  69 6a 6b                             | ijk",
+	}
+
+	assert.Equal(t, expectedLines, mapStrings(result, stripANSI))
+
+	result = VisualizeByteDiffWithOptions(actual, expected, Options{
+		WindowByteCount:   12,
+		BytesPerRow:       8,
+		ShouldShowOffsets: true,
+		OffsetFormat:      OffsetFormatDecimal,
+	})
+
+	assert.Equal(t, "      10  61 62 63 64 65 66 67 68              | abcdefgh", stripANSI(result[1]))
+}
+
+func TestVisualizeByteDiffSideBySide(t *testing.T) {
+	actual := []byte("Hello, World!")
+	expected := []byte("Hello, Go!")
+
+	result := VisualizeByteDiffWithOptions(actual, expected, Options{
+		BytesPerRow: 8,
+		Layout:      LayoutSideBySide,
+	})
+
+	expectedLines := []string{
+		"Expected (bytes 0-13), hexadecimal: | ASCII:   || Actual (bytes 0-13), hexadecimal:   | ASCII:",
+		"48 65 6c 6c 6f 2c 20 47             | Hello, G || 48 65 6c 6c 6f 2c 20 57             | Hello, W",
+		"6f 21                               | o!       || 6f 72 6c 64 21                      | orld!",
+	}
+
+	assert.Equal(t, expectedLines, mapStrings(result, stripANSI))
+}
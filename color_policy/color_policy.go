@@ -0,0 +1,75 @@
+package color_policy
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+)
+
+// ColorPolicy controls whether ANSI color codes are emitted by the logger and bytes_diff_visualizer packages.
+type ColorPolicy string
+
+const (
+	// Always emits colors regardless of where output is going. This is the default, unless logs are formatted as JSON.
+	Always ColorPolicy = "always"
+
+	// Never emits colors.
+	Never ColorPolicy = "never"
+
+	// Auto emits colors only if stdout is a terminal. Stdout is checked even if output is written elsewhere (like the
+	// writer passed to tester_utils.RunCLIWithOutput).
+	Auto ColorPolicy = "auto"
+)
+
+// Parse converts a user-provided value (like "auto") into a ColorPolicy. An empty value is treated as Always.
+func Parse(value string) (ColorPolicy, error) {
+	switch ColorPolicy(value) {
+	case "", Always:
+		return Always, nil
+	case Never:
+		return Never, nil
+	case Auto:
+		return Auto, nil
+	default:
+		return "", fmt.Errorf("unknown color policy %q, expected one of: always, never, auto", value)
+	}
+}
+
+// FromEnv returns the ColorPolicy configured via CODECRAFTERS_COLOR.
+//
+// NO_COLOR (https://no-color.org) is respected unless CODECRAFTERS_COLOR is explicitly set to "always".
+func FromEnv(env map[string]string) (ColorPolicy, error) {
+	policy, err := Parse(env["CODECRAFTERS_COLOR"])
+	if err != nil {
+		return "", err
+	}
+
+	if env["NO_COLOR"] != "" && env["CODECRAFTERS_COLOR"] != string(Always) {
+		return Never, nil
+	}
+
+	return policy, nil
+}
+
+// IsEnabled returns true if colors should be emitted under this policy.
+func (p ColorPolicy) IsEnabled() bool {
+	switch p {
+	case Never:
+		return false
+	case Auto:
+		return isatty.IsTerminal(os.Stdout.Fd()) || isatty.IsCygwinTerminal(os.Stdout.Fd())
+	default:
+		return true
+	}
+}
+
+// Apply enables or disables colors for all packages in tester-utils. tester_utils.RunCLI calls this with the configured
+// policy. Until then, fatih/color's defaults apply.
+//
+// This must be called before any loggers are used, since color settings are global. Loggers can also override the
+// global setting using logger.Options.ColorPolicy.
+func Apply(policy ColorPolicy) {
+	color.NoColor = !policy.IsEnabled()
+}
@@ -0,0 +1,46 @@
+package color_policy
+
+import (
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromEnv(t *testing.T) {
+	tests := []struct {
+		env            map[string]string
+		expectedPolicy ColorPolicy
+	}{
+		{map[string]string{}, Always},
+		{map[string]string{"CODECRAFTERS_COLOR": "never"}, Never},
+		{map[string]string{"CODECRAFTERS_COLOR": "auto"}, Auto},
+		{map[string]string{"NO_COLOR": "1"}, Never},
+		{map[string]string{"NO_COLOR": "1", "CODECRAFTERS_COLOR": "auto"}, Never},
+		{map[string]string{"NO_COLOR": "1", "CODECRAFTERS_COLOR": "always"}, Always},
+		{map[string]string{"NO_COLOR": ""}, Always},
+	}
+
+	for _, tt := range tests {
+		policy, err := FromEnv(tt.env)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		assert.Equal(t, tt.expectedPolicy, policy, "env: %v", tt.env)
+	}
+
+	_, err := FromEnv(map[string]string{"CODECRAFTERS_COLOR": "rainbow"})
+	assert.ErrorContains(t, err, "unknown color policy")
+}
+
+func TestApply(t *testing.T) {
+	originalNoColor := color.NoColor
+	defer func() { color.NoColor = originalNoColor }()
+
+	Apply(Never)
+	assert.True(t, color.NoColor)
+
+	Apply(Always)
+	assert.False(t, color.NoColor)
+}
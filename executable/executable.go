@@ -28,6 +28,9 @@ type Executable struct {
 	// WorkingDir can be set before calling Start or Run to customize the working directory of the executable.
 	WorkingDir string
 
+	// LineWriterOptions can be set before calling Start or Run to customize how output is relayed to the logger.
+	LineWriterOptions linewriter.Options
+
 	Process *os.Process
 
 	StdinPipe io.WriteCloser
@@ -46,11 +49,17 @@ type Executable struct {
 	readDone           chan bool
 }
 
+// ErrTimedOut is returned by Run and Wait if the program doesn't exit within TimeoutInMilliseconds
+var ErrTimedOut = errors.New("execution timed out")
+
 // ExecutableResult holds the result of an executable run
 type ExecutableResult struct {
 	Stdout   []byte
 	Stderr   []byte
 	ExitCode int
+
+	// PartialLineCount is the number of stdout/stderr lines that didn't end with a newline when relayed to the logger
+	PartialLineCount int
 }
 
 type loggerWriter struct {
@@ -78,6 +87,7 @@ func (e *Executable) Clone() *Executable {
 		TimeoutInMilliseconds: e.TimeoutInMilliseconds,
 		loggerFunc:            e.loggerFunc,
 		WorkingDir:            e.WorkingDir,
+		LineWriterOptions:     e.LineWriterOptions,
 	}
 }
 
@@ -147,7 +157,7 @@ func (e *Executable) Start(args ...string) error {
 	}
 	e.stdoutBytes = []byte{}
 	e.stdoutBuffer = bytes.NewBuffer(e.stdoutBytes)
-	e.stdoutLineWriter = linewriter.New(newLoggerWriter(e.loggerFunc), 500*time.Millisecond)
+	e.stdoutLineWriter = linewriter.NewWithOptions(newLoggerWriter(e.loggerFunc), 500*time.Millisecond, e.LineWriterOptions)
 
 	// Setup stderr relay
 	e.stderrPipe, err = cmd.StderrPipe()
@@ -156,7 +166,7 @@ func (e *Executable) Start(args ...string) error {
 	}
 	e.stderrBytes = []byte{}
 	e.stderrBuffer = bytes.NewBuffer(e.stderrBytes)
-	e.stderrLineWriter = linewriter.New(newLoggerWriter(e.loggerFunc), 500*time.Millisecond)
+	e.stderrLineWriter = linewriter.NewWithOptions(newLoggerWriter(e.loggerFunc), 500*time.Millisecond, e.LineWriterOptions)
 
 	e.StdinPipe, err = cmd.StdinPipe()
 	if err != nil {
@@ -276,13 +286,14 @@ func (e *Executable) Wait() (ExecutableResult, error) {
 	stderr := e.stderrBuffer.Bytes()
 
 	result := ExecutableResult{
-		Stdout:   stdout,
-		Stderr:   stderr,
-		ExitCode: exitCode,
+		Stdout:           stdout,
+		Stderr:           stderr,
+		ExitCode:         exitCode,
+		PartialLineCount: e.stdoutLineWriter.PartialLineCount() + e.stderrLineWriter.PartialLineCount(),
 	}
 
 	if e.ctxWithTimeout.Err() == context.DeadlineExceeded {
-		return ExecutableResult{}, fmt.Errorf("execution timed out")
+		return ExecutableResult{}, ErrTimedOut
 	}
 	return result, nil
 }
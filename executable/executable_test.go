@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/fatih/color"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -82,6 +83,31 @@ func TestOutputCapture(t *testing.T) {
 	assert.Equal(t, "hey\n", string(result.Stderr))
 }
 
+func TestPartialLineMarker(t *testing.T) {
+	originalNoColor := color.NoColor
+	defer func() { color.NoColor = originalNoColor }()
+	color.NoColor = true
+
+	loggedLines := []string{}
+
+	e := NewVerboseExecutable("./test_helpers/stdout_printf.sh", func(line string) {
+		loggedLines = append(loggedLines, line)
+	})
+	e.LineWriterOptions.ShouldMarkPartialLines = true
+
+	result, err := e.Run("hey")
+	assert.NoError(t, err)
+	assert.Equal(t, "hey", string(result.Stdout))
+	assert.Equal(t, 1, result.PartialLineCount)
+	assert.Equal(t, []string{"hey ⏎ missing"}, loggedLines)
+
+	loggedLines = []string{}
+	result, err = e.Run("hey\n")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.PartialLineCount)
+	assert.Equal(t, []string{"hey"}, loggedLines)
+}
+
 func TestLargeOutputCapture(t *testing.T) {
 	e := NewExecutable("./test_helpers/large_echo.sh")
 	result, err := e.Run("hey")
@@ -0,0 +1,66 @@
+package inspectable_byte_string
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/width"
+)
+
+// runeDisplayWidth returns the number of terminal columns r takes up
+func runeDisplayWidth(r rune) int {
+	// Combining marks, zero width joiners & variation selectors attach to the previous rune
+	if unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r) || r == 0x200D || (r >= 0xFE00 && r <= 0xFE0F) {
+		return 0
+	}
+
+	// Terminals render East Asian Wide (which includes most emoji) & Fullwidth runes as 2 columns
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// displayWidth returns the number of terminal columns s takes up
+func displayWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		width += runeDisplayWidth(r)
+	}
+
+	return width
+}
+
+// runeStartBefore returns the start of the valid UTF-8 rune that contains the byte at index i, or i if there is none
+// (i.e. i is already at a rune start, or is part of an invalid sequence).
+func runeStartBefore(value []byte, i int) int {
+	for j := i; j >= 0 && j > i-utf8.UTFMax; j-- {
+		if j >= len(value) || !utf8.RuneStart(value[j]) {
+			continue
+		}
+
+		r, size := utf8.DecodeRune(value[j:])
+		if r != utf8.RuneError && j+size > i {
+			return j
+		}
+
+		return i
+	}
+
+	return i
+}
+
+// runeEndAfter returns the end of the valid UTF-8 rune that contains the byte just before index i, so that value[:i]
+// doesn't end in the middle of a rune.
+func runeEndAfter(value []byte, i int) int {
+	if i <= 0 || i >= len(value) {
+		return i
+	}
+
+	start := runeStartBefore(value, i-1)
+	_, size := utf8.DecodeRune(value[start:])
+
+	return max(i, start+size)
+}
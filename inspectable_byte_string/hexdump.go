@@ -0,0 +1,117 @@
+package inspectable_byte_string
+
+import (
+	"fmt"
+	"strings"
+)
+
+const hexdumpBytesPerRow = 16
+
+// hexdumpOffsetGutterWidth is the width of "00000000  "
+const hexdumpOffsetGutterWidth = 10
+
+// hexdumpRows renders bytes like `hexdump -C`. Offsets are relative to the original (untruncated) bytes.
+//
+// For example:
+//
+// > 00000000  2a 32 0d 0a 24 33 0d 0a  53 45 54 0d 0a           |*2..$3..SET..|
+func (s InspectableByteString) hexdumpRows() []string {
+	rows := []string{}
+
+	for rowStart := 0; rowStart < len(s.bytes); rowStart += hexdumpBytesPerRow {
+		rowEnd := min(rowStart+hexdumpBytesPerRow, len(s.bytes))
+
+		hexColumn := ""
+		asciiColumn := ""
+
+		for i := rowStart; i < rowStart+hexdumpBytesPerRow; i++ {
+			if i-rowStart == hexdumpBytesPerRow/2 {
+				hexColumn += " "
+			}
+
+			if i < rowEnd {
+				hexColumn += fmt.Sprintf("%02x ", s.bytes[i])
+				asciiColumn += formatByteAsAscii(s.bytes[i])
+			} else {
+				hexColumn += "   "
+			}
+		}
+
+		rows = append(rows, fmt.Sprintf("%08x  %s |%s|", s.truncationStartIndex+rowStart, hexColumn, asciiColumn))
+	}
+
+	return rows
+}
+
+// formatHexdumpWithHighlights returns the hexdump with each highlight annotated below the row it starts in
+//
+// For example, if called with highlights {Offset: 2, Length: 2, Text: "found terminator here"} and formattedString
+// "Received: ", the return value will be:
+//
+// > Received:
+// > 00000000  2a 32 0d 0a 24 33 0d 0a                           |*2..$3..|
+// >                 ^^^^^ found terminator here
+func (s InspectableByteString) formatHexdumpWithHighlights(highlights []Highlight, formattedStringPrefix string, formattedStringSuffix string) string {
+	if len(highlights) > 0 {
+		s = s.TruncateAroundRange(highlightsRange(highlights))
+	}
+
+	lines := []string{}
+
+	if header := strings.TrimSpace(formattedStringPrefix + formattedStringSuffix); header != "" {
+		lines = append(lines, header)
+	}
+
+	rows := s.hexdumpRows()
+	if len(rows) == 0 {
+		rows = append(rows, fmt.Sprintf("%08x", s.truncationStartIndex))
+	}
+
+	for rowIndex, row := range rows {
+		lines = append(lines, row)
+
+		rowStart := s.truncationStartIndex + rowIndex*hexdumpBytesPerRow
+		isLastRow := rowIndex == len(rows)-1
+
+		for _, highlight := range highlights {
+			isInRow := highlight.Offset >= rowStart && highlight.Offset < rowStart+hexdumpBytesPerRow
+
+			// Offsets past the end (like "expected more bytes here") are shown on the last row
+			if !isInRow && !(isLastRow && highlight.Offset >= rowStart+hexdumpBytesPerRow) {
+				continue
+			}
+
+			// Past-the-end offsets point at the slot right after the last byte
+			byteCountInRow := min(hexdumpBytesPerRow, len(s.bytes)-rowIndex*hexdumpBytesPerRow)
+			firstIndexInRow := min(highlight.Offset-rowStart, byteCountInRow)
+			lastIndexInRow := min(highlight.Offset+highlight.normalizedLength()-1-rowStart, byteCountInRow-1)
+			lastIndexInRow = max(lastIndexInRow, firstIndexInRow)
+
+			caretStart := hexdumpColumnForIndex(firstIndexInRow)
+			caretEnd := hexdumpColumnForIndex(lastIndexInRow) + 2
+
+			offsetPointerLine := strings.Repeat(" ", caretStart) + strings.Repeat("^", caretEnd-caretStart) + " " + highlight.Text
+			lines = append(lines, offsetPointerLine)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// hexdumpColumnForIndex returns the column at which the hex representation of the byte at indexInRow starts
+func hexdumpColumnForIndex(indexInRow int) int {
+	column := hexdumpOffsetGutterWidth + indexInRow*3
+	if indexInRow >= hexdumpBytesPerRow/2 {
+		column += 1
+	}
+
+	return column
+}
+
+func formatByteAsAscii(b byte) string {
+	if b < 32 || b > 126 {
+		return "."
+	}
+
+	return string(b)
+}
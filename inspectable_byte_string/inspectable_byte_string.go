@@ -5,16 +5,53 @@ import (
 	"strings"
 )
 
+const defaultWindowSize = 30
+
+type RenderMode string
+
+const (
+	// RenderModeQuoted renders bytes as a Go-quoted string, like "+OK\r\n". This is the default.
+	RenderModeQuoted RenderMode = "quoted"
+
+	// RenderModeHexdump renders bytes like `hexdump -C`, with an offset gutter and an ASCII column
+	RenderModeHexdump RenderMode = "hexdump"
+)
+
 type InspectableByteString struct {
 	bytes []byte
 
 	truncationStartIndex int
+
+	// windowSize is the number of bytes kept when truncating. Zero means defaultWindowSize.
+	windowSize int
+
+	// renderMode is the format used by FormattedString and FormatWithHighlights. Empty means RenderModeQuoted.
+	renderMode RenderMode
+}
+
+// Highlight marks Length bytes starting at Offset with an annotation. A Length of 0 or 1 is rendered as a single caret.
+type Highlight struct {
+	Offset int
+	Length int
+	Text   string
 }
 
 func NewInspectableByteString(bytes []byte) InspectableByteString {
 	return InspectableByteString{bytes: bytes}
 }
 
+// WithWindowSize returns a copy that keeps windowSize bytes (instead of 30) when truncating around highlights.
+func (s InspectableByteString) WithWindowSize(windowSize int) InspectableByteString {
+	s.windowSize = windowSize
+	return s
+}
+
+// WithRenderMode returns a copy that is formatted using renderMode.
+func (s InspectableByteString) WithRenderMode(renderMode RenderMode) InspectableByteString {
+	s.renderMode = renderMode
+	return s
+}
+
 // FormatWithHighlightedOffset returns a string that represents the bytes with the byteOffset highlighted
 //
 // For example, if called with highlightOffset 4, highlightText "error" and formattedString "Received: ", the return value will be:
@@ -22,32 +59,113 @@ func NewInspectableByteString(bytes []byte) InspectableByteString {
 // > Received: "+OK\r\n"
 // >                 ^ error
 func (s InspectableByteString) FormatWithHighlightedOffset(highlightOffset int, highlightText string, formattedStringPrefix string, formattedStringSuffix string) string {
-	s = s.TruncateAroundOffset(highlightOffset)
+	return s.FormatWithHighlights([]Highlight{{Offset: highlightOffset, Length: 1, Text: highlightText}}, formattedStringPrefix, formattedStringSuffix)
+}
+
+// FormatWithHighlights returns a string that represents the bytes with each highlight annotated on its own line
+//
+// For example, if called with highlights {Offset: 1, Length: 1, Text: "expected length here"} and {Offset: 2,
+// Length: 2, Text: "found terminator here"}, and formattedString "Received: ", the return value will be:
+//
+// > Received: "*2\r\n$3\r\n"
+// >             ^ expected length here
+// >              ^^^^ found terminator here
+//
+// The bytes are truncated to a window that covers all highlights. See formatHexdumpWithHighlights for how this
+// looks in RenderModeHexdump.
+func (s InspectableByteString) FormatWithHighlights(highlights []Highlight, formattedStringPrefix string, formattedStringSuffix string) string {
+	if s.renderMode == RenderModeHexdump {
+		return s.formatHexdumpWithHighlights(highlights, formattedStringPrefix, formattedStringSuffix)
+	}
+
+	if len(highlights) == 0 {
+		return fmt.Sprintf("%s%s%s", formattedStringPrefix, s.FormattedString(), formattedStringSuffix)
+	}
+
+	s = s.TruncateAroundRange(highlightsRange(highlights))
 
 	lines := []string{}
 
 	lines = append(lines, fmt.Sprintf("%s%s%s", formattedStringPrefix, s.FormattedString(), formattedStringSuffix))
 
-	offsetPointerLine := ""
-	offsetPointerLine += strings.Repeat(" ", len(formattedStringPrefix)+s.GetOffsetInFormattedString(highlightOffset))
-	offsetPointerLine += "^ " + highlightText
-	lines = append(lines, offsetPointerLine)
+	for _, highlight := range highlights {
+		highlightStartInFormattedString := s.GetOffsetInFormattedString(highlight.Offset)
+
+		// Ranges cover every formatted character (escape sequences like \r\n included), single bytes get one caret
+		caretCount := 1
+		if highlight.Length > 1 {
+			highlightEnd := min(highlight.Offset+highlight.Length, s.truncationStartIndex+len(s.bytes))
+			highlightEnd = s.truncationStartIndex + runeEndAfter(s.bytes, highlightEnd-s.truncationStartIndex)
+			caretCount = max(1, s.GetOffsetInFormattedString(highlightEnd)-highlightStartInFormattedString)
+		}
+
+		offsetPointerLine := ""
+		offsetPointerLine += strings.Repeat(" ", displayWidth(formattedStringPrefix)+highlightStartInFormattedString)
+		offsetPointerLine += strings.Repeat("^", caretCount) + " " + highlight.Text
+		lines = append(lines, offsetPointerLine)
+	}
 
 	return strings.Join(lines, "\n")
 }
 
+func (h Highlight) normalizedLength() int {
+	return max(1, h.Length)
+}
+
+// highlightsRange returns the smallest range that covers all highlights
+func highlightsRange(highlights []Highlight) (int, int) {
+	rangeStart, rangeEnd := highlights[0].Offset, highlights[0].Offset+highlights[0].normalizedLength()
+	for _, highlight := range highlights[1:] {
+		rangeStart = min(rangeStart, highlight.Offset)
+		rangeEnd = max(rangeEnd, highlight.Offset+highlight.normalizedLength())
+	}
+
+	return rangeStart, rangeEnd
+}
+
 func (s InspectableByteString) FormattedString() string {
+	if s.renderMode == RenderModeHexdump {
+		return strings.Join(s.hexdumpRows(), "\n")
+	}
+
 	return fmt.Sprintf("%q", string(s.bytes))
 }
 
 func (s InspectableByteString) TruncateAroundOffset(offset int) InspectableByteString {
-	// We've got about 50 characters to use in the terminal line.
-	start := max(0, offset-20)
-	end := max(0, min(len(s.bytes), start+30))
+	return s.TruncateAroundRange(offset, offset+1)
+}
+
+// TruncateAroundRange keeps a window of bytes (30 by default, see WithWindowSize) around start:end. If the range is
+// longer than the window, the window is extended so that the whole range is kept. In RenderModeHexdump, the window is
+// extended to whole rows.
+func (s InspectableByteString) TruncateAroundRange(start int, end int) InspectableByteString {
+	windowSize := s.windowSize
+	if windowSize <= 0 {
+		windowSize = defaultWindowSize
+	}
+
+	// We've got about 50 characters to use in the terminal line. 2/3rds of the window is used for bytes before the range.
+	truncationStart := max(0, start-(windowSize*2/3))
+	truncationEnd := max(truncationStart+windowSize, end)
+
+	if s.renderMode == RenderModeHexdump {
+		truncationStart = truncationStart / hexdumpBytesPerRow * hexdumpBytesPerRow
+		truncationEnd = (truncationEnd + hexdumpBytesPerRow - 1) / hexdumpBytesPerRow * hexdumpBytesPerRow
+	}
+
+	truncationEnd = max(0, min(len(s.bytes), truncationEnd))
+
+	// Avoid cutting multibyte characters in half, they'd be rendered as escape sequences otherwise
+	if s.renderMode != RenderModeHexdump {
+		truncationStart = runeStartBefore(s.bytes, truncationStart)
+		truncationEnd = runeEndAfter(s.bytes, truncationEnd)
+	}
 
 	return InspectableByteString{
-		bytes:                s.bytes[start:end],
-		truncationStartIndex: start,
+		bytes:                s.bytes[truncationStart:truncationEnd],
+		truncationStartIndex: truncationStart,
+		windowSize:           s.windowSize,
+		renderMode:           s.renderMode,
 	}
 }
 
@@ -57,11 +175,16 @@ func (s InspectableByteString) TruncateAroundOffset(offset int) InspectableByteS
 //   - If the string is "+OK\r\n"
 //   - And byteOffset is 4 (i.e. \n, the 5th byte)
 //   - The return value will be 6 (i.e. the 6th character in the formatted string)
+//
+// The offset is measured in terminal columns, so wide characters (like CJK or emoji) count as 2. If byteOffset
+// points inside a multibyte character, the offset of that character is returned.
 func (s InspectableByteString) GetOffsetInFormattedString(byteOffset int) int {
 	if s.truncationStartIndex != 0 {
 		byteOffset = byteOffset - s.truncationStartIndex
 	}
 
+	byteOffset = runeStartBefore(s.bytes, byteOffset)
+
 	formattedBytesBefore := fmt.Sprintf("%q", string(s.bytes[:byteOffset]))
-	return len(formattedBytesBefore) - 1
+	return displayWidth(formattedBytesBefore) - 1
 }
@@ -58,3 +58,184 @@ Received: "+OK\r\n" (suffix)
 
 	assert.Equal(t, expected, result)
 }
+
+func TestFormatWithHighlights(t *testing.T) {
+	ibs := NewInspectableByteString([]byte("*2\r\n$3\r\n"))
+
+	expected := strings.Join([]string{
+		`Received: "*2\r\n$3\r\n"`,
+		`            ^ expected length here`,
+		`             ^^^^ found terminator here`,
+	}, "\n")
+
+	result := ibs.FormatWithHighlights([]Highlight{
+		{Offset: 1, Length: 1, Text: "expected length here"},
+		{Offset: 2, Length: 2, Text: "found terminator here"},
+	}, "Received: ", "")
+
+	assert.Equal(t, expected, result)
+}
+
+func TestFormatWithHighlightsKeepsAllHighlightsInWindow(t *testing.T) {
+	bytes := []byte{}
+	for i := 0; i < 10; i++ {
+		bytes = append(bytes, []byte(fmt.Sprintf("helloworld%d", i))...)
+	}
+
+	ibs := NewInspectableByteString(bytes).WithWindowSize(10)
+
+	expected := strings.Join([]string{
+		`"oworld2helloworld3helloworld4"`,
+		`       ^ first`,
+		`                            ^^ second`,
+	}, "\n")
+
+	result := ibs.FormatWithHighlights([]Highlight{
+		{Offset: 32, Text: "first"},
+		{Offset: 53, Length: 2, Text: "second"},
+	}, "", "")
+
+	assert.Equal(t, expected, result)
+}
+
+func TestTruncateWithWindowSize(t *testing.T) {
+	bytes := []byte{}
+	for i := 0; i < 10; i++ {
+		bytes = append(bytes, []byte(fmt.Sprintf("helloworld%d", i))...)
+	}
+
+	ibs := NewInspectableByteString(bytes).WithWindowSize(60)
+	assert.Equal(t, `"d1helloworld2helloworld3helloworld4helloworld5helloworld6hel"`, ibs.TruncateAroundOffset(60).FormattedString())
+}
+
+func TestHexdumpFormattedString(t *testing.T) {
+	ibs := NewInspectableByteString([]byte("*2\r\n$3\r\nSET\r\n$3\r\nfoo\r\n$3\r\nbar\r\n")).WithRenderMode(RenderModeHexdump)
+
+	expected := strings.Join([]string{
+		`00000000  2a 32 0d 0a 24 33 0d 0a  53 45 54 0d 0a 24 33 0d  |*2..$3..SET..$3.|`,
+		`00000010  0a 66 6f 6f 0d 0a 24 33  0d 0a 62 61 72 0d 0a     |.foo..$3..bar..|`,
+	}, "\n")
+
+	assert.Equal(t, expected, ibs.FormattedString())
+}
+
+func TestHexdumpFormatWithHighlights(t *testing.T) {
+	ibs := NewInspectableByteString([]byte("*2\r\n$3\r\nSET\r\n$3\r\nfoo\r\n$3\r\nbar\r\n")).WithRenderMode(RenderModeHexdump)
+
+	expected := strings.Join([]string{
+		`Received:`,
+		`00000000  2a 32 0d 0a 24 33 0d 0a  53 45 54 0d 0a 24 33 0d  |*2..$3..SET..$3.|`,
+		`                ^^^^^ found terminator here`,
+		`00000010  0a 66 6f 6f 0d 0a 24 33  0d 0a 62 61 72 0d 0a     |.foo..$3..bar..|`,
+		`                                                        ^^ expected more bytes here`,
+	}, "\n")
+
+	result := ibs.FormatWithHighlights([]Highlight{
+		{Offset: 2, Length: 2, Text: "found terminator here"},
+		{Offset: 31, Text: "expected more bytes here"},
+	}, "Received: ", "")
+
+	assert.Equal(t, expected, result)
+}
+
+func TestHexdumpTruncatesToWholeRows(t *testing.T) {
+	bytes := make([]byte, 256)
+	for i := range bytes {
+		bytes[i] = byte(i)
+	}
+
+	ibs := NewInspectableByteString(bytes).WithRenderMode(RenderModeHexdump)
+
+	expected := strings.Join([]string{
+		`00000050  50 51 52 53 54 55 56 57  58 59 5a 5b 5c 5d 5e 5f  |PQRSTUVWXYZ[\]^_|`,
+		`00000060  60 61 62 63 64 65 66 67  68 69 6a 6b 6c 6d 6e 6f  |` + "`" + `abcdefghijklmno|`,
+		`00000070  70 71 72 73 74 75 76 77  78 79 7a 7b 7c 7d 7e 7f  |pqrstuvwxyz{|}~.|`,
+		`          ^^ error`,
+	}, "\n")
+
+	assert.Equal(t, expected, ibs.FormatWithHighlightedOffset(0x70, "error", "", ""))
+}
+
+func TestGetOffsetInFormattedStringWithWideCharacters(t *testing.T) {
+	// "日本" is 2 CJK characters (3 bytes each, 2 columns each), followed by "\n"
+	ibs := NewInspectableByteString([]byte("日本\n"))
+
+	assert.Equal(t, `"日本\n"`, ibs.FormattedString())
+	assert.Equal(t, 1, ibs.GetOffsetInFormattedString(0))
+	assert.Equal(t, 3, ibs.GetOffsetInFormattedString(3))
+	assert.Equal(t, 5, ibs.GetOffsetInFormattedString(6))
+
+	// Offsets inside a character point at the character
+	assert.Equal(t, 3, ibs.GetOffsetInFormattedString(4))
+	assert.Equal(t, 3, ibs.GetOffsetInFormattedString(5))
+}
+
+func TestFormatWithHighlightedOffsetWithEmoji(t *testing.T) {
+	ibs := NewInspectableByteString([]byte("hi 👋 there\r\n"))
+
+	expected := strings.Join([]string{
+		`Received: "hi 👋 there\r\n"`,
+		`                      ^ error`,
+	}, "\n")
+
+	assert.Equal(t, expected, ibs.FormatWithHighlightedOffset(13, "error", "Received: ", ""))
+
+	expected = strings.Join([]string{
+		`Received: "hi 👋 there\r\n"`,
+		`              ^^ wave`,
+	}, "\n")
+
+	assert.Equal(t, expected, ibs.FormatWithHighlights([]Highlight{{Offset: 3, Length: 2, Text: "wave"}}, "Received: ", ""))
+}
+
+func TestRuneDisplayWidth(t *testing.T) {
+	assert.Equal(t, 1, runeDisplayWidth('a'))
+	assert.Equal(t, 1, runeDisplayWidth('é'))
+	assert.Equal(t, 2, runeDisplayWidth('日'))
+	assert.Equal(t, 2, runeDisplayWidth('Ａ'))          // U+FF21, fullwidth
+	assert.Equal(t, 2, runeDisplayWidth('🚀'))          // U+1F680, transport & map symbols
+	assert.Equal(t, 2, runeDisplayWidth('\U0001FA70')) // Symbols & pictographs extended-A
+	assert.Equal(t, 2, runeDisplayWidth('\U0001FAE0'))
+	assert.Equal(t, 2, runeDisplayWidth('✅')) // U+2705
+	assert.Equal(t, 2, runeDisplayWidth('⚡')) // U+26A1
+	assert.Equal(t, 0, runeDisplayWidth('\u0301'))
+	assert.Equal(t, 0, runeDisplayWidth('\u200D'))
+}
+
+func TestFormatWithHighlightedOffsetAfterWideSymbols(t *testing.T) {
+	ibs := NewInspectableByteString([]byte("🚀✅⚡!"))
+
+	expected := strings.Join([]string{
+		`"🚀✅⚡!"`,
+		`       ^ error`,
+	}, "\n")
+
+	assert.Equal(t, expected, ibs.FormatWithHighlightedOffset(10, "error", "", ""))
+}
+
+func TestTruncateAroundOffsetDoesNotSplitCharacters(t *testing.T) {
+	bytes := []byte(strings.Repeat("日", 20))
+
+	// 60 bytes total, the window would start at byte 40 - 20 = 20 (in the middle of the 7th character)
+	truncated := NewInspectableByteString(bytes).TruncateAroundOffset(40)
+
+	assert.Equal(t, 18, truncated.truncationStartIndex)
+	assert.Equal(t, `"`+strings.Repeat("日", 11)+`"`, truncated.FormattedString())
+}
+
+func TestInvalidUTF8(t *testing.T) {
+	ibs := NewInspectableByteString([]byte("a\xe6\x97b\xffc"))
+
+	assert.Equal(t, `"a\xe6\x97b\xffc"`, ibs.FormattedString())
+	assert.Equal(t, 2, ibs.GetOffsetInFormattedString(1))
+	assert.Equal(t, 6, ibs.GetOffsetInFormattedString(2))
+	assert.Equal(t, 10, ibs.GetOffsetInFormattedString(3))
+	assert.Equal(t, 15, ibs.GetOffsetInFormattedString(5))
+
+	expected := strings.Join([]string{
+		`"a\xe6\x97b\xffc"`,
+		`           ^^^^^ invalid bytes`,
+	}, "\n")
+
+	assert.Equal(t, expected, ibs.FormatWithHighlights([]Highlight{{Offset: 4, Length: 2, Text: "invalid bytes"}}, "", ""))
+}
@@ -0,0 +1,5 @@
+package internal
+
+// IsRecordingOrEvaluatingFixtures is set while testing.TestTesterOutput runs. It lives here instead of the testing
+// package so that packages like logger can read it without an import cycle.
+var IsRecordingOrEvaluatingFixtures = false
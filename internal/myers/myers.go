@@ -0,0 +1,241 @@
+// Package myers implements Myers' O(ND) difference algorithm, shared by the byte and line diff visualizers.
+package myers
+
+type OperationType int
+
+const (
+	Equal OperationType = iota
+
+	// Delete means that elements are present in a, but not in b
+	Delete
+
+	// Insert means that elements are present in b, but not in a
+	Insert
+)
+
+// Operation describes a run of elements that are equal, deleted from a or inserted from b.
+//
+// AStart:AEnd is the range of elements in a, BStart:BEnd is the range of elements in b. For Delete operations the
+// b range is empty, for Insert operations the a range is empty.
+type Operation struct {
+	Type   OperationType
+	AStart int
+	AEnd   int
+	BStart int
+	BEnd   int
+}
+
+// maxEditDistance caps running time, which grows with the product of the input length and the edit distance. Beyond
+// this, the differing section is reported as a single deletion + insertion.
+const maxEditDistance = 2048
+
+// Diff returns the shortest sequence of operations that turns a into b.
+//
+// This uses the linear space refinement from Myers' paper: the "middle snake" of the shortest path is found by
+// searching from both ends at once, and the sections before and after it are diffed recursively.
+func Diff[T comparable](a []T, b []T) []Operation {
+	d := &differ[T]{
+		a:       a,
+		b:       b,
+		forward: make([]int, maxEditDistance+3),
+		reverse: make([]int, maxEditDistance+3),
+		builder: &operationsBuilder{},
+	}
+
+	d.diff(0, len(a), 0, len(b))
+
+	return groupChanges(d.builder.operations)
+}
+
+// groupChanges lists deletions before insertions in each section between equal runs, like most diff tools do. The
+// recursive search can produce them in any order.
+func groupChanges(operations []Operation) []Operation {
+	builder := &operationsBuilder{}
+
+	for i := 0; i < len(operations); {
+		if operations[i].Type == Equal {
+			builder.add(Equal, operations[i].AStart, operations[i].BStart, operations[i].AEnd-operations[i].AStart)
+			i++
+			continue
+		}
+
+		j := i
+		for j < len(operations) && operations[j].Type != Equal {
+			j++
+		}
+
+		first, last := operations[i], operations[j-1]
+		builder.add(Delete, first.AStart, first.BStart, last.AEnd-first.AStart)
+		builder.add(Insert, last.AEnd, first.BStart, last.BEnd-first.BStart)
+		i = j
+	}
+
+	return builder.operations
+}
+
+type differ[T comparable] struct {
+	a []T
+	b []T
+
+	// forward[k] and reverse[k] hold the furthest x reached on diagonal k by searches from the start and the end of the
+	// section being diffed, see findMiddleSnake. Both are offset by maxEditDistance/2 + 1, so that k can be negative.
+	forward []int
+	reverse []int
+
+	builder *operationsBuilder
+}
+
+// diff adds operations that turn a[aStart:aEnd] into b[bStart:bEnd] to the builder
+func (d *differ[T]) diff(aStart int, aEnd int, bStart int, bEnd int) {
+	prefixLength := 0
+	for aStart+prefixLength < aEnd && bStart+prefixLength < bEnd && d.a[aStart+prefixLength] == d.b[bStart+prefixLength] {
+		prefixLength++
+	}
+
+	d.builder.add(Equal, aStart, bStart, prefixLength)
+	aStart += prefixLength
+	bStart += prefixLength
+
+	suffixLength := 0
+	for aStart < aEnd-suffixLength && bStart < bEnd-suffixLength && d.a[aEnd-1-suffixLength] == d.b[bEnd-1-suffixLength] {
+		suffixLength++
+	}
+
+	aEnd -= suffixLength
+	bEnd -= suffixLength
+
+	if aStart == aEnd || bStart == bEnd {
+		d.builder.add(Delete, aStart, bStart, aEnd-aStart)
+		d.builder.add(Insert, aEnd, bStart, bEnd-bStart)
+	} else if snake, ok := d.findMiddleSnake(aStart, aEnd, bStart, bEnd); ok {
+		d.diff(aStart, snake.xStart, bStart, snake.yStart)
+		d.builder.add(Equal, snake.xStart, snake.yStart, snake.xEnd-snake.xStart)
+		d.diff(snake.xEnd, aEnd, snake.yEnd, bEnd)
+	} else {
+		d.builder.add(Delete, aStart, bStart, aEnd-aStart)
+		d.builder.add(Insert, aEnd, bStart, bEnd-bStart)
+	}
+
+	d.builder.add(Equal, aEnd, bEnd, suffixLength)
+}
+
+// snake is a (possibly empty) run of equal elements on the shortest path, from (xStart, yStart) to (xEnd, yEnd)
+type snake struct {
+	xStart int
+	yStart int
+	xEnd   int
+	yEnd   int
+}
+
+// findMiddleSnake returns the snake in the middle of the shortest path that turns a[aStart:aEnd] into
+// b[bStart:bEnd]. The sections must be non-empty, and must not start or end with equal elements. Returns false if the
+// edit distance exceeds maxEditDistance.
+//
+// Searching from both ends, the paths meet after about half the edit distance. The sections before and after the
+// middle snake each have a smaller edit distance, so recursing on them always terminates.
+func (d *differ[T]) findMiddleSnake(aStart int, aEnd int, bStart int, bEnd int) (snake, bool) {
+	n, m := aEnd-aStart, bEnd-bStart
+
+	// Diagonal k in the forward search is diagonal delta-k in the reverse search
+	delta := n - m
+	isDeltaOdd := delta%2 != 0
+
+	maxD := min((n+m+1)/2, maxEditDistance/2)
+	vOffset := maxEditDistance/2 + 1
+	d.forward[vOffset+1] = 0
+	d.reverse[vOffset+1] = 0
+
+	for D := 0; D <= maxD; D++ {
+		for k := -D; k <= D; k += 2 {
+			var x int
+			if k == -D || (k != D && d.forward[vOffset+k-1] < d.forward[vOffset+k+1]) {
+				x = d.forward[vOffset+k+1] // Move down, i.e. insert from b
+			} else {
+				x = d.forward[vOffset+k-1] + 1 // Move right, i.e. delete from a
+			}
+
+			xStart := x
+			for x < n && x-k < m && d.a[aStart+x] == d.b[bStart+x-k] {
+				x++
+			}
+
+			d.forward[vOffset+k] = x
+
+			// Only reverse paths of length D-1 have been computed so far, so the overlap check uses those
+			reverseK := delta - k
+			if isDeltaOdd && reverseK >= -(D-1) && reverseK <= D-1 && x+d.reverse[vOffset+reverseK] >= n {
+				return snake{
+					xStart: aStart + xStart,
+					yStart: bStart + xStart - k,
+					xEnd:   aStart + x,
+					yEnd:   bStart + x - k,
+				}, true
+			}
+		}
+
+		// The reverse search walks back from the end, using the same logic on reversed inputs
+		for k := -D; k <= D; k += 2 {
+			var x int
+			if k == -D || (k != D && d.reverse[vOffset+k-1] < d.reverse[vOffset+k+1]) {
+				x = d.reverse[vOffset+k+1]
+			} else {
+				x = d.reverse[vOffset+k-1] + 1
+			}
+
+			xStart := x
+			for x < n && x-k < m && d.a[aEnd-1-x] == d.b[bEnd-1-(x-k)] {
+				x++
+			}
+
+			d.reverse[vOffset+k] = x
+
+			forwardK := delta - k
+			if !isDeltaOdd && forwardK >= -D && forwardK <= D && x+d.forward[vOffset+forwardK] >= n {
+				return snake{
+					xStart: aEnd - x,
+					yStart: bEnd - (x - k),
+					xEnd:   aEnd - xStart,
+					yEnd:   bEnd - (xStart - k),
+				}, true
+			}
+		}
+	}
+
+	return snake{}, false
+}
+
+// operationsBuilder merges adjacent operations of the same type
+type operationsBuilder struct {
+	operations []Operation
+}
+
+func (b *operationsBuilder) add(operationType OperationType, aStart int, bStart int, length int) {
+	if length == 0 {
+		return
+	}
+
+	aLength, bLength := length, length
+	switch operationType {
+	case Delete:
+		bLength = 0
+	case Insert:
+		aLength = 0
+	}
+
+	if len(b.operations) > 0 {
+		last := &b.operations[len(b.operations)-1]
+		if last.Type == operationType && last.AEnd == aStart && last.BEnd == bStart {
+			last.AEnd += aLength
+			last.BEnd += bLength
+			return
+		}
+	}
+
+	b.operations = append(b.operations, Operation{
+		Type:   operationType,
+		AStart: aStart,
+		AEnd:   aStart + aLength,
+		BStart: bStart,
+		BEnd:   bStart + bLength,
+	})
+}
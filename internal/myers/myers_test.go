@@ -0,0 +1,143 @@
+package myers
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// apply reconstructs b from a using operations, and checks that equal runs really are equal
+func apply(t *testing.T, a []byte, b []byte, operations []Operation) []byte {
+	result := []byte{}
+	aIndex, bIndex := 0, 0
+
+	for _, operation := range operations {
+		assert.Equal(t, aIndex, operation.AStart)
+		assert.Equal(t, bIndex, operation.BStart)
+
+		switch operation.Type {
+		case Equal:
+			assert.Equal(t, a[operation.AStart:operation.AEnd], b[operation.BStart:operation.BEnd])
+			result = append(result, a[operation.AStart:operation.AEnd]...)
+		case Insert:
+			result = append(result, b[operation.BStart:operation.BEnd]...)
+		}
+
+		aIndex, bIndex = operation.AEnd, operation.BEnd
+	}
+
+	assert.Equal(t, len(a), aIndex)
+	assert.Equal(t, len(b), bIndex)
+
+	return result
+}
+
+func editDistance(operations []Operation) int {
+	distance := 0
+	for _, operation := range operations {
+		distance += (operation.AEnd - operation.AStart) + (operation.BEnd - operation.BStart)
+		if operation.Type == Equal {
+			distance -= 2 * (operation.AEnd - operation.AStart)
+		}
+	}
+
+	return distance
+}
+
+// lcsEditDistance computes the edit distance using the textbook dynamic programming approach
+func lcsEditDistance(a []byte, b []byte) int {
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
+				lengths[i][j] = lengths[i-1][j-1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i-1][j], lengths[i][j-1])
+			}
+		}
+	}
+
+	return len(a) + len(b) - 2*lengths[len(a)][len(b)]
+}
+
+func TestDiffIdentical(t *testing.T) {
+	operations := Diff([]byte("abc"), []byte("abc"))
+	assert.Equal(t, []Operation{{Type: Equal, AStart: 0, AEnd: 3, BStart: 0, BEnd: 3}}, operations)
+
+	assert.Empty(t, Diff([]byte{}, []byte{}))
+}
+
+func TestDiffInsertion(t *testing.T) {
+	operations := Diff([]byte("blob\000header"), []byte("blob\000\000header"))
+
+	assert.Equal(t, []Operation{
+		{Type: Equal, AStart: 0, AEnd: 5, BStart: 0, BEnd: 5},
+		{Type: Insert, AStart: 5, AEnd: 5, BStart: 5, BEnd: 6},
+		{Type: Equal, AStart: 5, AEnd: 11, BStart: 6, BEnd: 12},
+	}, operations)
+}
+
+func TestDiffClassicExample(t *testing.T) {
+	a, b := []byte("ABCABBA"), []byte("CBABAC")
+	operations := Diff(a, b)
+
+	assert.Equal(t, b, apply(t, a, b, operations))
+	assert.Equal(t, 5, editDistance(operations))
+}
+
+func TestDiffRandomInputs(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	for range 200 {
+		a := make([]byte, r.Intn(40))
+		b := make([]byte, r.Intn(40))
+		for i := range a {
+			a[i] = byte('a' + r.Intn(3))
+		}
+		for i := range b {
+			b[i] = byte('a' + r.Intn(3))
+		}
+
+		operations := Diff(a, b)
+		assert.Equal(t, b, apply(t, a, b, operations))
+		assert.Equal(t, lcsEditDistance(a, b), editDistance(operations))
+	}
+}
+
+func TestDiffLargeInputsWithFewChanges(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	a := make([]byte, 20000)
+	r.Read(a)
+
+	b := append([]byte{}, a...)
+	b[100] ^= 0xff
+	b = append(b[:5000], b[5010:]...)
+	b = append(b[:15000], append([]byte("inserted"), b[15000:]...)...)
+
+	operations := Diff(a, b)
+	assert.Equal(t, b, apply(t, a, b, operations))
+	assert.Equal(t, 2+10+8, editDistance(operations))
+}
+
+func TestDiffFallsBackForLargeEditDistances(t *testing.T) {
+	a := make([]byte, maxEditDistance)
+	b := make([]byte, maxEditDistance)
+	for i := range a {
+		a[i] = 'a'
+		b[i] = 'b'
+	}
+
+	operations := Diff(append([]byte("x"), a...), append([]byte("x"), b...))
+
+	assert.Equal(t, []Operation{
+		{Type: Equal, AStart: 0, AEnd: 1, BStart: 0, BEnd: 1},
+		{Type: Delete, AStart: 1, AEnd: maxEditDistance + 1, BStart: 1, BEnd: 1},
+		{Type: Insert, AStart: maxEditDistance + 1, AEnd: maxEditDistance + 1, BStart: 1, BEnd: maxEditDistance + 1},
+	}, operations)
+}
@@ -0,0 +1,165 @@
+package line_diff_visualizer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/make-core/tester-utils/internal/myers"
+)
+
+const defaultContextLineCount = 3
+
+// VisualizeLineDiff returns a unified diff (like `diff -u expected actual`, minus the file headers) of two multi-line
+// strings, with 3 lines of context around each change.
+//
+// Removed (expected) lines are prefixed with "-" and colored red, added (actual) lines are prefixed with "+" and
+// colored green. The lines will include ANSI escape codes unless colors are disabled (see color_policy).
+//
+// If both strings are equal, an empty slice is returned.
+func VisualizeLineDiff(actual string, expected string) []string {
+	return VisualizeLineDiffWithContext(actual, expected, defaultContextLineCount)
+}
+
+// VisualizeLineDiffWithContext is like VisualizeLineDiff, but with a custom number of context lines around each change.
+func VisualizeLineDiffWithContext(actual string, expected string, contextLineCount int) []string {
+	if actual == expected {
+		return []string{}
+	}
+
+	contextLineCount = max(contextLineCount, 0)
+
+	// Lines include their trailing newline (if any), so that a missing newline at the end counts as a difference
+	expectedLines := splitLines(expected)
+	actualLines := splitLines(actual)
+
+	operations := myers.Diff(expectedLines, actualLines)
+
+	lines := []string{}
+
+	for _, hunk := range groupIntoHunks(operations, contextLineCount) {
+		expectedStart, expectedEnd := hunk[0].AStart, hunk[len(hunk)-1].AEnd
+		actualStart, actualEnd := hunk[0].BStart, hunk[len(hunk)-1].BEnd
+
+		lines = append(lines, color.New(color.FgCyan).Sprintf(
+			"@@ -%s +%s @@",
+			formatRange(expectedStart, expectedEnd),
+			formatRange(actualStart, actualEnd),
+		))
+
+		for _, operation := range hunk {
+			switch operation.Type {
+			case myers.Equal:
+				lines = appendLines(lines, " ", expectedLines[operation.AStart:operation.AEnd], nil)
+			case myers.Delete:
+				lines = appendLines(lines, "-", expectedLines[operation.AStart:operation.AEnd], color.New(color.FgRed))
+			case myers.Insert:
+				lines = appendLines(lines, "+", actualLines[operation.BStart:operation.BEnd], color.New(color.FgGreen))
+			}
+		}
+	}
+
+	return lines
+}
+
+// groupIntoHunks splits operations into hunks, trimming equal runs down to contextLineCount lines around changes.
+// Changes separated by at most 2*contextLineCount equal lines end up in the same hunk.
+func groupIntoHunks(operations []myers.Operation, contextLineCount int) [][]myers.Operation {
+	hunks := [][]myers.Operation{}
+	currentHunk := []myers.Operation{}
+
+	for i, operation := range operations {
+		if operation.Type != myers.Equal {
+			currentHunk = append(currentHunk, operation)
+			continue
+		}
+
+		length := operation.AEnd - operation.AStart
+		isFirst := i == 0
+		isLast := i == len(operations)-1
+
+		switch {
+		case isFirst:
+			skipped := max(0, length-contextLineCount)
+			currentHunk = append(currentHunk, shiftEqual(operation, skipped, length))
+		case isLast:
+			currentHunk = append(currentHunk, shiftEqual(operation, 0, min(length, contextLineCount)))
+		case length <= 2*contextLineCount:
+			currentHunk = append(currentHunk, operation)
+		default:
+			currentHunk = append(currentHunk, shiftEqual(operation, 0, contextLineCount))
+			hunks = append(hunks, currentHunk)
+			currentHunk = []myers.Operation{shiftEqual(operation, length-contextLineCount, length)}
+		}
+	}
+
+	hunks = append(hunks, currentHunk)
+
+	// Drop empty equal operations (when contextLineCount is 0)
+	for i, hunk := range hunks {
+		nonEmpty := []myers.Operation{}
+		for _, operation := range hunk {
+			if operation.AEnd > operation.AStart || operation.BEnd > operation.BStart {
+				nonEmpty = append(nonEmpty, operation)
+			}
+		}
+
+		hunks[i] = nonEmpty
+	}
+
+	return hunks
+}
+
+// shiftEqual returns the part of an equal operation between offsets start and end
+func shiftEqual(operation myers.Operation, start int, end int) myers.Operation {
+	return myers.Operation{
+		Type:   myers.Equal,
+		AStart: operation.AStart + start,
+		AEnd:   operation.AStart + end,
+		BStart: operation.BStart + start,
+		BEnd:   operation.BStart + end,
+	}
+}
+
+// formatRange formats a 0-indexed [start, end) line range the way `diff -u` does
+func formatRange(start int, end int) string {
+	count := end - start
+
+	switch count {
+	case 0:
+		// An empty range refers to the line just before it
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, count)
+	}
+}
+
+func appendLines(lines []string, prefix string, diffLines []string, lineColor *color.Color) []string {
+	for _, line := range diffLines {
+		formatted := prefix + strings.TrimSuffix(line, "\n")
+		if lineColor != nil {
+			formatted = lineColor.Sprint(formatted)
+		}
+
+		lines = append(lines, formatted)
+
+		if !strings.HasSuffix(line, "\n") {
+			lines = append(lines, `\ No newline at end of file`)
+		}
+	}
+
+	return lines
+}
+
+func splitLines(value string) []string {
+	lines := strings.SplitAfter(value, "\n")
+
+	// SplitAfter returns a trailing empty string if value ends with a newline
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
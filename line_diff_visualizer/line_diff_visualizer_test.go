@@ -0,0 +1,107 @@
+package line_diff_visualizer
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	color.NoColor = true
+
+	os.Exit(m.Run())
+}
+
+func TestVisualizeLineDiffEqual(t *testing.T) {
+	assert.Equal(t, []string{}, VisualizeLineDiff("a\nb\n", "a\nb\n"))
+}
+
+func TestVisualizeLineDiffSingleChange(t *testing.T) {
+	expected := "one\ntwo\nthree\nfour\nfive\nsix\nseven\n"
+	actual := "one\ntwo\nthree\nFOUR\nfive\nsix\nseven\n"
+
+	assert.Equal(t, []string{
+		"@@ -1,7 +1,7 @@",
+		" one",
+		" two",
+		" three",
+		"-four",
+		"+FOUR",
+		" five",
+		" six",
+		" seven",
+	}, VisualizeLineDiff(actual, expected))
+
+	assert.Equal(t, []string{
+		"@@ -3,3 +3,3 @@",
+		" three",
+		"-four",
+		"+FOUR",
+		" five",
+	}, VisualizeLineDiffWithContext(actual, expected, 1))
+}
+
+func TestVisualizeLineDiffMultipleHunks(t *testing.T) {
+	expectedLines := []string{}
+	for _, c := range "abcdefghijklmnop" {
+		expectedLines = append(expectedLines, string(c))
+	}
+
+	actualLines := append([]string{}, expectedLines...)
+	actualLines = append(actualLines[:1], actualLines[2:]...) // Remove "b"
+	actualLines[13] = "O"                                     // Change "o"
+
+	expected := strings.Join(expectedLines, "\n") + "\n"
+	actual := strings.Join(actualLines, "\n") + "\n"
+
+	assert.Equal(t, []string{
+		"@@ -1,5 +1,4 @@",
+		" a",
+		"-b",
+		" c",
+		" d",
+		" e",
+		"@@ -12,5 +11,5 @@",
+		" l",
+		" m",
+		" n",
+		"-o",
+		"+O",
+		" p",
+	}, VisualizeLineDiff(actual, expected))
+}
+
+func TestVisualizeLineDiffInsertionIntoEmpty(t *testing.T) {
+	assert.Equal(t, []string{
+		"@@ -0,0 +1,2 @@",
+		"+a",
+		"+b",
+	}, VisualizeLineDiff("a\nb\n", ""))
+}
+
+func TestVisualizeLineDiffMissingNewline(t *testing.T) {
+	assert.Equal(t, []string{
+		"@@ -1,2 +1,2 @@",
+		" a",
+		"-b",
+		"+b",
+		`\ No newline at end of file`,
+	}, VisualizeLineDiff("a\nb", "a\nb\n"))
+}
+
+func TestVisualizeLineDiffColors(t *testing.T) {
+	originalNoColor := color.NoColor
+	defer func() { color.NoColor = originalNoColor }()
+	color.NoColor = false
+
+	lines := VisualizeLineDiff("b\n", "a\n")
+
+	assert.Equal(t, []string{
+		color.New(color.FgCyan).Sprint("@@ -1 +1 @@"),
+		color.New(color.FgRed).Sprint("-a"),
+		color.New(color.FgGreen).Sprint("+b"),
+	}, lines)
+}
@@ -3,102 +3,314 @@ package linewriter
 import (
 	"bytes"
 	"io"
+	"regexp"
+	"sync"
 	"time"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
 )
 
+// CarriageReturnMode controls how \r is handled
+type CarriageReturnMode int
+
+const (
+	// CarriageReturnPreserve writes \r as-is. This is the default.
+	CarriageReturnPreserve CarriageReturnMode = iota
+
+	// CarriageReturnAsSeparator treats \r as a line terminator, just like \n. \r\n is treated as a single terminator.
+	CarriageReturnAsSeparator
+
+	// CarriageReturnAsOverwrite discards everything before a \r on the same line, like a terminal does when rendering
+	// progress bars. \r\n is treated as a single terminator.
+	CarriageReturnAsOverwrite
+)
+
+// ANSIMode controls how ANSI escape sequences (colors, cursor movement etc.) are handled
+type ANSIMode int
+
+const (
+	// ANSIPreserve writes escape sequences as-is. This is the default.
+	ANSIPreserve ANSIMode = iota
+
+	// ANSIStrip removes escape sequences.
+	ANSIStrip
+
+	// ANSIEscape replaces the ESC character with a visible "\x1b", so that escape sequences are shown instead of
+	// interpreted by the terminal.
+	ANSIEscape
+)
+
+// Options controls how a LineWriter handles special characters
+type Options struct {
+	CarriageReturnMode CarriageReturnMode
+	ANSIMode           ANSIMode
+
+	// ShouldMarkPartialLines appends a dimmed "⏎ missing" marker to a line that still doesn't have a trailing newline
+	// when Flush is called (usually when the program exits), so that users can tell that their program didn't print one.
+	ShouldMarkPartialLines bool
+}
+
+// ansiEscapeSequenceRegex matches CSI sequences (like colors), OSC sequences (like window titles) and other
+// two-character escape sequences.
+var ansiEscapeSequenceRegex = regexp.MustCompile("\x1b(?:\\[[0-?]*[ -/]*[@-~]|\\][^\x07\x1b]*(?:\x07|\x1b\\\\)|[@-Z\\\\-_])")
+
+// LineWriter buffers writes and forwards them to the underlying writer one line at a time.
+//
+// If a partial line isn't terminated within the timeout, it's written out with a trailing newline. If the terminator
+// arrives later, it doesn't produce an extra empty line.
 type LineWriter struct {
-	buffer           chan byte
-	flushableStrings chan string
-	writer           io.Writer
-	timeout          time.Duration
-	lastErr          error
-	flushChan        chan bool
-	flushedChan      chan bool
+	mutex   sync.Mutex
+	writer  io.Writer
+	timeout time.Duration
+	options Options
+	lastErr error
+
+	// pending holds the bytes of a line that hasn't been terminated yet
+	pending []byte
+
+	// partialLineCount is the number of times Flush found a line that wasn't terminated
+	partialLineCount int
+
+	// isTimedOutLineOpen is true if a partial line was written out on timeout, and its terminator hasn't arrived yet
+	isTimedOutLineOpen bool
+
+	// timer flushes pending bytes if no more writes arrive within timeout
+	timer *time.Timer
+
+	// timerGeneration is incremented every time the timer is reset, so that a stale timer that already fired doesn't
+	// flush a line that was written after it was scheduled.
+	timerGeneration int
+}
+
+// New returns a LineWriter instance
+func New(w io.Writer, timeout time.Duration) *LineWriter {
+	return NewWithOptions(w, timeout, Options{})
 }
 
-// Write queues a string for writing
-func (w *LineWriter) Write(bytes []byte) (n int, err error) {
-	for _, byte := range bytes {
-		w.buffer <- byte
+// NewWithOptions returns a LineWriter instance that handles special characters as specified in options
+func NewWithOptions(w io.Writer, timeout time.Duration, options Options) *LineWriter {
+	return &LineWriter{
+		writer:  w,
+		timeout: timeout,
+		options: options,
 	}
+}
 
-	return len(bytes), nil
+// Write queues bytes for writing, complete lines are written out immediately
+func (w *LineWriter) Write(p []byte) (n int, err error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	terminators := "\n"
+	if w.options.CarriageReturnMode != CarriageReturnPreserve {
+		terminators = "\n\r"
+	}
+
+	// Pending bytes never contain a terminator (except a trailing \r, see below), so there's no need to search them again
+	searchFrom := len(w.pending)
+	if searchFrom > 0 && w.pending[searchFrom-1] == '\r' && w.options.CarriageReturnMode != CarriageReturnPreserve {
+		searchFrom--
+	}
+
+	w.pending = append(w.pending, p...)
+
+	lineStart := 0
+	for {
+		terminatorIndex := bytes.IndexAny(w.pending[searchFrom:], terminators)
+		if terminatorIndex == -1 {
+			break
+		}
+
+		terminatorIndex += searchFrom
+
+		if w.pending[terminatorIndex] == '\n' {
+			w.writeLine(w.pending[lineStart : terminatorIndex+1])
+			lineStart = terminatorIndex + 1
+			searchFrom = lineStart
+			continue
+		}
+
+		// This is a \r, we need to look at the next byte to know whether it's part of \r\n
+		if terminatorIndex+1 == len(w.pending) {
+			break
+		}
+
+		if w.pending[terminatorIndex+1] == '\n' {
+			w.writeLine(append(w.pending[lineStart:terminatorIndex:terminatorIndex], '\n'))
+			lineStart = terminatorIndex + 2
+		} else if w.options.CarriageReturnMode == CarriageReturnAsSeparator {
+			w.writeLine(append(w.pending[lineStart:terminatorIndex:terminatorIndex], '\n'))
+			lineStart = terminatorIndex + 1
+		} else {
+			lineStart = terminatorIndex + 1 // CarriageReturnAsOverwrite: discard what's been written so far
+		}
+
+		searchFrom = lineStart
+	}
+
+	// Shift the partial line (if any) to the start of the buffer so that the underlying array can be reused
+	if lineStart > 0 {
+		w.pending = w.pending[:copy(w.pending, w.pending[lineStart:])]
+	}
+
+	if len(w.pending) > 0 {
+		w.resetTimer()
+	} else {
+		w.stopTimer()
+	}
+
+	return len(p), nil
 }
 
 // Flush flushes any pending strings, and returns an error if any writes failed
 // in the past
 func (w *LineWriter) Flush() (err error) {
-	w.flushChan <- true
-	<-w.flushedChan
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.stopTimer()
+
+	if w.flushPending(len(w.pending), w.options.ShouldMarkPartialLines) || w.isTimedOutLineOpen {
+		w.partialLineCount++
+	}
+
+	w.isTimedOutLineOpen = false
+
 	return w.lastErr
 }
 
-// New returns a LineWriter instance
-func New(w io.Writer, timeout time.Duration) *LineWriter {
-	lw := &LineWriter{
-		buffer:           make(chan byte),
-		flushableStrings: make(chan string),
-		writer:           w,
-		timeout:          timeout,
-		flushChan:        make(chan bool),
-		flushedChan:      make(chan bool),
-	}
-	lw.startReader()
-	lw.startWriter()
-	return lw
+// PartialLineCount returns the number of times Flush found a line without a trailing newline. Lines that were written
+// out on timeout aren't counted if their terminator arrives later. Useful for hints like "your output did not end
+// with a newline".
+func (w *LineWriter) PartialLineCount() int {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.partialLineCount
 }
 
-func (w *LineWriter) startReader() {
-	go func(w *LineWriter) {
-		accumulated := bytes.NewBuffer([]byte{})
-
-	loop:
-		for {
-			select {
-			case b := <-w.buffer:
-				accumulated.WriteByte(b)
-
-				if string(b) == "\n" {
-					w.flushableStrings <- accumulated.String()
-					accumulated = bytes.NewBuffer([]byte{})
-				}
-			case <-time.After(w.timeout):
-				if accumulated.Len() > 0 {
-					accumulated.WriteByte('\n')
-					w.flushableStrings <- accumulated.String()
-					accumulated = bytes.NewBuffer([]byte{})
-				}
-			case <-w.flushChan:
-				if accumulated.Len() > 0 {
-					accumulated.WriteByte('\n')
-					w.flushableStrings <- accumulated.String()
-					accumulated = bytes.NewBuffer([]byte{})
-				}
-				close(w.flushableStrings)
-				break loop
-			}
+// flushOnTimeout writes out a partial line, except for a trailing incomplete UTF-8 character (if any). The rest of
+// the character is likely to arrive in a later write. A trailing \r is held back too, since it might be followed by
+// \n. Flushing it on its own would emit an extra blank line. Must be called with the mutex held.
+func (w *LineWriter) flushOnTimeout() {
+	byteCount := len(w.pending) - incompleteRuneSuffixLength(w.pending)
+	if byteCount > 0 && w.pending[byteCount-1] == '\r' {
+		byteCount--
+	}
+
+	if w.flushPending(byteCount, false) {
+		w.isTimedOutLineOpen = true
+	}
+}
+
+// flushPending writes out the first byteCount bytes of a partial line with a trailing newline. Returns true if the
+// line wasn't terminated. Must be called with the mutex held.
+func (w *LineWriter) flushPending(byteCount int, shouldMarkPartialLine bool) bool {
+	if byteCount == 0 {
+		return false
+	}
+
+	line := w.pending[:byteCount]
+	isPartialLine := true
+
+	// A trailing \r can't be part of \r\n anymore, so it terminates the line
+	if w.options.CarriageReturnMode != CarriageReturnPreserve && line[len(line)-1] == '\r' {
+		line = line[:len(line)-1]
+		isPartialLine = false
+		w.isTimedOutLineOpen = false
+	}
+
+	// The line was already written out when it timed out, a \r on its own would only add a blank line
+	if w.isTimedOutLineOpen && bytes.Equal(line, []byte{'\r'}) {
+		line = nil
+	}
+
+	if len(line) > 0 {
+		line = w.transformLine(line[:len(line):len(line)]) // Limiting capacity ensures appends don't overwrite pending bytes
+
+		if isPartialLine && shouldMarkPartialLine {
+			line = append(line, " "+color.New(color.Faint).Sprint("⏎ missing")...)
+		}
+
+		w.writeTransformedLine(append(line, '\n'))
+	}
+
+	w.pending = w.pending[:copy(w.pending, w.pending[byteCount:])]
+
+	return isPartialLine
+}
+
+// writeLine writes a single line (including the trailing newline) to the underlying writer. Must be called with the
+// mutex held.
+func (w *LineWriter) writeLine(line []byte) {
+	if w.isTimedOutLineOpen {
+		w.isTimedOutLineOpen = false
+
+		// The line was already written out with a newline when it timed out, this is just its terminator
+		if len(line) == 1 || bytes.Equal(line, []byte("\r\n")) {
+			return
+		}
+	}
+
+	w.writeTransformedLine(w.transformLine(line))
+}
+
+// transformLine handles ANSI escape sequences as specified in options
+func (w *LineWriter) transformLine(line []byte) []byte {
+	switch w.options.ANSIMode {
+	case ANSIStrip:
+		return ansiEscapeSequenceRegex.ReplaceAll(line, nil)
+	case ANSIEscape:
+		return bytes.ReplaceAll(line, []byte("\x1b"), []byte(`\x1b`))
+	default:
+		return line
+	}
+}
+
+func (w *LineWriter) writeTransformedLine(line []byte) {
+	if _, err := w.writer.Write(line); err != nil {
+		w.lastErr = err
+	}
+}
+
+func (w *LineWriter) resetTimer() {
+	w.stopTimer()
+
+	generation := w.timerGeneration
+	w.timer = time.AfterFunc(w.timeout, func() {
+		w.mutex.Lock()
+		defer w.mutex.Unlock()
+
+		if generation == w.timerGeneration {
+			w.flushOnTimeout()
 		}
-	}(w)
+	})
+}
+
+func (w *LineWriter) stopTimer() {
+	w.timerGeneration++
+
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
 }
 
-func (w *LineWriter) startWriter() {
-	go func(w *LineWriter) {
-	loop:
-		for {
-			select {
-			case str, more := <-w.flushableStrings:
-				if str != "" {
-					_, err := w.writer.Write([]byte(str))
-					if err != nil {
-						w.lastErr = err
-					}
-				}
-
-				if !more {
-					w.flushedChan <- true
-					break loop
-				}
+// incompleteRuneSuffixLength returns the number of bytes at the end of value that form the start of a multi-byte UTF-8
+// character, but not a complete one.
+func incompleteRuneSuffixLength(value []byte) int {
+	for length := 1; length < utf8.UTFMax && length <= len(value); length++ {
+		suffix := value[len(value)-length:]
+
+		if utf8.RuneStart(suffix[0]) {
+			if utf8.FullRune(suffix) {
+				return 0
 			}
+
+			return length
 		}
-	}(w)
+	}
+
+	return 0
 }
@@ -2,9 +2,11 @@ package linewriter
 
 import (
 	"bytes"
+	"io"
 	"testing"
 	"time"
 
+	"github.com/fatih/color"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -74,3 +76,237 @@ func TestHandlesTimeout(t *testing.T) {
 		t.FailNow()
 	}
 }
+
+func TestFlushWritesPartialLine(t *testing.T) {
+	w := bytes.NewBuffer([]byte{})
+	lw := New(w, time.Second)
+	lw.Write([]byte("abc\nde"))
+	lw.Write([]byte("f"))
+
+	if !assert.Equal(t, "abc\n", w.String()) {
+		t.FailNow()
+	}
+
+	if !assert.Nil(t, lw.Flush()) {
+		t.FailNow()
+	}
+
+	if !assert.Equal(t, "abc\ndef\n", w.String()) {
+		t.FailNow()
+	}
+
+	// Writes after a flush should still work
+	lw.Write([]byte("ghi\n"))
+	assert.Equal(t, "abc\ndef\nghi\n", w.String())
+}
+
+type lineCountingWriter struct {
+	lines []string
+}
+
+func (w *lineCountingWriter) Write(p []byte) (n int, err error) {
+	w.lines = append(w.lines, string(p))
+	return len(p), nil
+}
+
+func TestWritesOneLineAtATime(t *testing.T) {
+	w := &lineCountingWriter{}
+	lw := New(w, time.Second)
+	lw.Write([]byte("a\nb"))
+	lw.Write([]byte("c\nd\n"))
+	lw.Flush()
+
+	assert.Equal(t, []string{"a\n", "bc\n", "d\n"}, w.lines)
+}
+
+func benchmarkWrite(b *testing.B, lineLength int) {
+	line := append(bytes.Repeat([]byte("x"), lineLength-1), '\n')
+	chunk := bytes.Repeat(line, (32*1024)/lineLength) // io.Copy uses 32KB chunks
+	totalBytes := 4 * 1024 * 1024
+
+	b.SetBytes(int64(totalBytes))
+	b.ResetTimer()
+
+	for range b.N {
+		lw := New(io.Discard, 500*time.Millisecond)
+		for written := 0; written < totalBytes; written += len(chunk) {
+			lw.Write(chunk)
+		}
+		lw.Flush()
+	}
+}
+
+func BenchmarkWriteShortLines(b *testing.B) {
+	benchmarkWrite(b, 16)
+}
+
+func BenchmarkWriteLongLines(b *testing.B) {
+	benchmarkWrite(b, 1024)
+}
+
+func BenchmarkWriteUnterminatedLine(b *testing.B) {
+	chunk := bytes.Repeat([]byte("x"), 32*1024)
+	totalBytes := 4 * 1024 * 1024
+
+	b.SetBytes(int64(totalBytes))
+	b.ResetTimer()
+
+	for range b.N {
+		lw := New(io.Discard, 500*time.Millisecond)
+		for written := 0; written < totalBytes; written += len(chunk) {
+			lw.Write(chunk)
+		}
+		lw.Flush()
+	}
+}
+
+func TestCarriageReturnAsSeparator(t *testing.T) {
+	w := bytes.NewBuffer([]byte{})
+	lw := NewWithOptions(w, time.Second, Options{CarriageReturnMode: CarriageReturnAsSeparator})
+	lw.Write([]byte("a\rb\r"))
+	lw.Write([]byte("\nc\r"))
+	lw.Flush()
+
+	assert.Equal(t, "a\nb\nc\n", w.String())
+}
+
+func TestCarriageReturnAsOverwrite(t *testing.T) {
+	w := bytes.NewBuffer([]byte{})
+	lw := NewWithOptions(w, time.Second, Options{CarriageReturnMode: CarriageReturnAsOverwrite})
+	lw.Write([]byte("10%\r50%\r"))
+	lw.Write([]byte("100%\r\ndone\r"))
+	lw.Flush()
+
+	assert.Equal(t, "100%\ndone\n", w.String())
+}
+
+func TestCarriageReturnPreservedByDefault(t *testing.T) {
+	w := bytes.NewBuffer([]byte{})
+	lw := New(w, time.Second)
+	lw.Write([]byte("a\rb\r\n"))
+	lw.Flush()
+
+	assert.Equal(t, "a\rb\r\n", w.String())
+}
+
+func TestANSIModes(t *testing.T) {
+	input := []byte("\x1b[31mred\x1b[0m \x1b]0;title\x07plain\n")
+
+	w := bytes.NewBuffer([]byte{})
+	lw := NewWithOptions(w, time.Second, Options{ANSIMode: ANSIStrip})
+	lw.Write(input)
+	assert.Equal(t, "red plain\n", w.String())
+
+	w = bytes.NewBuffer([]byte{})
+	lw = NewWithOptions(w, time.Second, Options{ANSIMode: ANSIEscape})
+	lw.Write(input)
+	assert.Equal(t, `\x1b[31mred\x1b[0m \x1b]0;title`+"\x07plain\n", w.String())
+
+	w = bytes.NewBuffer([]byte{})
+	lw = New(w, time.Second)
+	lw.Write(input)
+	assert.Equal(t, string(input), w.String())
+}
+
+func TestTimeoutDoesNotSplitRunes(t *testing.T) {
+	w := &lineCountingWriter{}
+	lw := New(w, 50*time.Millisecond)
+
+	smiley := []byte("😀")
+	lw.Write(append([]byte("hi "), smiley[:2]...))
+	time.Sleep(100 * time.Millisecond)
+
+	lw.Write(append(smiley[2:], '\n'))
+	lw.Flush()
+
+	assert.Equal(t, []string{"hi \n", "😀\n"}, w.lines)
+}
+
+func TestIncompleteRuneSuffixLength(t *testing.T) {
+	smiley := []byte("😀")
+
+	assert.Equal(t, 0, incompleteRuneSuffixLength([]byte("")))
+	assert.Equal(t, 0, incompleteRuneSuffixLength([]byte("abc")))
+	assert.Equal(t, 0, incompleteRuneSuffixLength(smiley))
+	assert.Equal(t, 1, incompleteRuneSuffixLength(smiley[:1]))
+	assert.Equal(t, 3, incompleteRuneSuffixLength(append([]byte("a"), smiley[:3]...)))
+	assert.Equal(t, 0, incompleteRuneSuffixLength([]byte{'a', 0xff}))
+}
+
+func TestMarksPartialLines(t *testing.T) {
+	originalNoColor := color.NoColor
+	defer func() { color.NoColor = originalNoColor }()
+	color.NoColor = true
+
+	w := bytes.NewBuffer([]byte{})
+	lw := NewWithOptions(w, 50*time.Millisecond, Options{ShouldMarkPartialLines: true})
+	lw.Write([]byte("abc\nde"))
+	time.Sleep(100 * time.Millisecond)
+
+	lw.Write([]byte("f"))
+	lw.Flush()
+
+	// "de" timed out, but it's only missing a newline if nothing else arrives before Flush
+	assert.Equal(t, "abc\nde\nf ⏎ missing\n", w.String())
+	assert.Equal(t, 1, lw.PartialLineCount())
+}
+
+func TestLateTerminatorAfterTimeout(t *testing.T) {
+	originalNoColor := color.NoColor
+	defer func() { color.NoColor = originalNoColor }()
+	color.NoColor = true
+
+	w := bytes.NewBuffer([]byte{})
+	lw := NewWithOptions(w, 50*time.Millisecond, Options{ShouldMarkPartialLines: true})
+	lw.Write([]byte("foo"))
+	time.Sleep(100 * time.Millisecond)
+
+	lw.Write([]byte("\nbar\n"))
+	lw.Flush()
+
+	assert.Equal(t, "foo\nbar\n", w.String())
+	assert.Equal(t, 0, lw.PartialLineCount())
+}
+
+func TestTimedOutLineWithoutTerminatorIsCounted(t *testing.T) {
+	w := bytes.NewBuffer([]byte{})
+	lw := New(w, 50*time.Millisecond)
+	lw.Write([]byte("foo"))
+	time.Sleep(100 * time.Millisecond)
+
+	lw.Flush()
+
+	assert.Equal(t, "foo\n", w.String())
+	assert.Equal(t, 1, lw.PartialLineCount())
+}
+
+func TestCountsPartialLinesWithoutMarking(t *testing.T) {
+	w := bytes.NewBuffer([]byte{})
+	lw := NewWithOptions(w, time.Second, Options{CarriageReturnMode: CarriageReturnAsSeparator})
+	lw.Write([]byte("abc\r"))
+	lw.Flush()
+	assert.Equal(t, 0, lw.PartialLineCount())
+
+	lw.Write([]byte("abc"))
+	lw.Flush()
+	assert.Equal(t, "abc\nabc\n", w.String())
+	assert.Equal(t, 1, lw.PartialLineCount())
+}
+
+func TestTimeoutDoesNotFlushCarriageReturnOnItsOwn(t *testing.T) {
+	for _, mode := range []CarriageReturnMode{CarriageReturnPreserve, CarriageReturnAsSeparator, CarriageReturnAsOverwrite} {
+		w := bytes.NewBuffer([]byte{})
+		lw := NewWithOptions(w, 50*time.Millisecond, Options{CarriageReturnMode: mode})
+		lw.Write([]byte("abc"))
+		time.Sleep(100 * time.Millisecond)
+
+		lw.Write([]byte("\r"))
+		time.Sleep(100 * time.Millisecond)
+
+		lw.Write([]byte("\n"))
+		lw.Flush()
+
+		assert.Equal(t, "abc\n", w.String(), "mode: %d", mode)
+		assert.Equal(t, 0, lw.PartialLineCount(), "mode: %d", mode)
+	}
+}
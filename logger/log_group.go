@@ -0,0 +1,177 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+)
+
+// LogGroups is a stack of log groups that are currently open. It's shared by all loggers that use the same
+// Options.LogGroups, so that lines from any of them are indented (and collapsed) along with the rest of the group.
+type LogGroups struct {
+	// mutex is held while lines are written, so that buffered and written lines stay in the order they were logged
+	mutex sync.Mutex
+	stack []*logGroup
+}
+
+// NewLogGroups returns LogGroups with no open groups.
+func NewLogGroups() *LogGroups {
+	return &LogGroups{}
+}
+
+// logGroup is a set of related log lines, see Logger.BeginGroup
+type logGroup struct {
+	title string
+
+	// titleLine is the rendered title, printed when the group starts (or ends, if collapsing)
+	titleLine string
+
+	// bufferedLines holds rendered lines until the group ends. Only used if Options.CollapseGroupsWithoutErrors is set.
+	bufferedLines []string
+
+	// hasError is true if an error was logged within this group (or any nested group). Once set, the group's buffered
+	// lines have been written out and later lines aren't buffered.
+	hasError bool
+}
+
+// BeginGroup starts a group of related log lines, like all commands sent by a single client. Lines logged before
+// the matching EndGroup are indented.
+//
+// When using GitHubActionsFormat, fold markers are emitted for top-level groups. When using JSONFormat, "group_start"
+// and "group_end" entries are emitted, and entries within the group list the group titles.
+func (l *Logger) BeginGroup(title string) {
+	title = l.options.Redactor.Redact(title)
+
+	groups := l.options.LogGroups
+	groups.mutex.Lock()
+	defer groups.mutex.Unlock()
+
+	if l.options.Format == JSONFormat {
+		if !l.IsQuiet {
+			l.writeJSON("group_start", title)
+		}
+
+		groups.stack = append(groups.stack, &logGroup{title: title})
+		return
+	}
+
+	if l.options.Format == GitHubActionsFormat && len(groups.stack) == 0 && !l.IsQuiet {
+		l.logger.Writer().Write(fmt.Appendf(nil, "::group::%s%s\n", l.prefix, title))
+	}
+
+	group := &logGroup{
+		title:     title,
+		titleLine: l.getLinePrefix() + l.infoColorize("%s", title)[0],
+	}
+
+	if !l.isCollapsingGroups() && !l.IsQuiet {
+		l.writeLine(group.titleLine+"\n", false)
+	}
+
+	groups.stack = append(groups.stack, group)
+}
+
+// EndGroup ends the group started by the last call to BeginGroup.
+//
+// Groups are shared by all loggers that use the same Options.LogGroups, so this ends the last group started by any of
+// them.
+func (l *Logger) EndGroup() {
+	groups := l.options.LogGroups
+	groups.mutex.Lock()
+	defer groups.mutex.Unlock()
+
+	l.endGroup()
+}
+
+// EndAllGroups ends all open groups, innermost first. Useful for cleaning up after a test case that returned without
+// ending its groups.
+func (l *Logger) EndAllGroups() {
+	groups := l.options.LogGroups
+	groups.mutex.Lock()
+	defer groups.mutex.Unlock()
+
+	for len(groups.stack) > 0 {
+		l.endGroup()
+	}
+}
+
+// endGroup must be called with LogGroups.mutex held
+func (l *Logger) endGroup() {
+	groups := l.options.LogGroups
+	if len(groups.stack) == 0 {
+		return
+	}
+
+	isCollapsingGroups := l.isCollapsingGroups()
+	group := groups.stack[len(groups.stack)-1]
+	groups.stack = groups.stack[:len(groups.stack)-1]
+
+	if l.options.Format == JSONFormat {
+		if !l.IsQuiet {
+			l.writeJSON("group_end", group.title)
+		}
+
+		return
+	}
+
+	// Groups with errors were already written out when the error was logged
+	if isCollapsingGroups && !group.hasError && !l.IsQuiet {
+		hiddenLinesSuffix := fmt.Sprintf(" (%d lines hidden)", len(group.bufferedLines))
+		l.writeLine(group.titleLine+l.infoColorize("%s", hiddenLinesSuffix)[0]+"\n", false)
+	}
+
+	if l.options.Format == GitHubActionsFormat && len(groups.stack) == 0 && !l.IsQuiet {
+		l.logger.Writer().Write([]byte("::endgroup::\n"))
+	}
+}
+
+// WithGroup runs fn within a log group, similar to WithAdditionalSecondaryPrefix.
+func (l *Logger) WithGroup(title string, fn func()) {
+	l.BeginGroup(title)
+	defer l.EndGroup()
+	fn()
+}
+
+func (l *Logger) isCollapsingGroups() bool {
+	return l.options.CollapseGroupsWithoutErrors && l.options.Format == TextFormat
+}
+
+// writeLine writes a rendered line, or buffers it if it's part of a group that might be collapsed.
+//
+// Errors are never held back: they expand all enclosing groups right away, so that they're visible even if the groups
+// are never ended (when a test fails or times out, for example).
+//
+// Must be called with LogGroups.mutex held.
+func (l *Logger) writeLine(line string, isError bool) {
+	stack := l.options.LogGroups.stack
+	if len(stack) > 0 && l.isCollapsingGroups() && !stack[len(stack)-1].hasError {
+		if !isError {
+			currentGroup := stack[len(stack)-1]
+			currentGroup.bufferedLines = append(currentGroup.bufferedLines, line)
+			return
+		}
+
+		l.expandGroups()
+	}
+
+	l.logger.Writer().Write([]byte(line))
+}
+
+// expandGroups writes out the titles and buffered lines of all open groups that haven't been expanded yet
+func (l *Logger) expandGroups() {
+	for _, group := range l.options.LogGroups.stack {
+		if group.hasError {
+			continue
+		}
+
+		if !l.IsQuiet {
+			l.logger.Writer().Write([]byte(group.titleLine + "\n"))
+		}
+
+		for _, line := range group.bufferedLines {
+			l.logger.Writer().Write([]byte(line))
+		}
+
+		group.bufferedLines = nil
+		group.hasError = true
+	}
+}
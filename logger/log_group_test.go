@@ -0,0 +1,150 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupsAreIndented(t *testing.T) {
+	buffer := bytes.NewBuffer([]byte{})
+	logger := GetLoggerWithOptions(false, "[test] ", Options{Sinks: []*Sink{NewSink(buffer)}})
+
+	logger.WithGroup("client-1", func() {
+		logger.Infof("$ redis-cli PING")
+		logger.WithGroup("response", func() {
+			logger.Infof("PONG")
+		})
+	})
+	logger.Infof("done")
+
+	expected := strings.Join([]string{
+		"[test] client-1",
+		"[test]   $ redis-cli PING",
+		"[test]   response",
+		"[test]     PONG",
+		"[test] done",
+	}, "\n") + "\n"
+
+	assert.Equal(t, expected, buffer.String())
+}
+
+func TestCollapsesGroupsWithoutErrors(t *testing.T) {
+	buffer := bytes.NewBuffer([]byte{})
+	logger := GetLoggerWithOptions(false, "", Options{CollapseGroupsWithoutErrors: true, Sinks: []*Sink{NewSink(buffer)}})
+
+	logger.WithGroup("client-1", func() {
+		logger.Infof("$ redis-cli PING")
+		logger.Infof("PONG")
+	})
+
+	logger.WithGroup("client-2", func() {
+		logger.Infof("$ redis-cli PING")
+		logger.WithGroup("response", func() {
+			logger.Errorf("expected PONG, got PING")
+		})
+	})
+
+	expected := strings.Join([]string{
+		"client-1 (2 lines hidden)",
+		"client-2",
+		"  $ redis-cli PING",
+		"  response",
+		"    expected PONG, got PING",
+	}, "\n") + "\n"
+
+	assert.Equal(t, expected, buffer.String())
+}
+
+func TestGroupsAreSharedWithClonesAndProgramLogger(t *testing.T) {
+	buffer := bytes.NewBuffer([]byte{})
+	options := Options{CollapseGroupsWithoutErrors: true, Sinks: []*Sink{NewSink(buffer)}, LogGroups: NewLogGroups()}
+	logger := GetLoggerWithOptions(false, "[test] ", options)
+	programLogger := GetLoggerWithOptions(false, "[your_program] ", options)
+	clientLogger := logger.Clone()
+	clientLogger.PushSecondaryPrefix("client-1")
+
+	logger.WithGroup("PING", func() {
+		programLogger.Plainln("received PING")
+		clientLogger.Infof("received PONG")
+	})
+
+	logger.WithGroup("ECHO", func() {
+		logger.Infof("$ redis-cli ECHO hey")
+		programLogger.Plainln("received ECHO")
+		clientLogger.Errorf("expected \"hey\", got nothing")
+		programLogger.Plainln("sent hey")
+	})
+
+	expected := strings.Join([]string{
+		"[test] PING (2 lines hidden)",
+		"[test] ECHO",
+		"[test]   $ redis-cli ECHO hey",
+		"[your_program]   received ECHO",
+		"[test] [client-1]   expected \"hey\", got nothing",
+		"[your_program]   sent hey",
+	}, "\n") + "\n"
+
+	assert.Equal(t, expected, buffer.String())
+}
+
+func TestErrorsExpandGroupsThatAreNeverEnded(t *testing.T) {
+	buffer := bytes.NewBuffer([]byte{})
+	logger := GetLoggerWithOptions(false, "", Options{CollapseGroupsWithoutErrors: true, Sinks: []*Sink{NewSink(buffer)}})
+
+	logger.BeginGroup("client-1")
+	logger.Infof("$ redis-cli PING")
+	logger.Errorf("expected PONG, got PING")
+	logger.Infof("Test failed")
+
+	expected := strings.Join([]string{
+		"client-1",
+		"  $ redis-cli PING",
+		"  expected PONG, got PING",
+		"  Test failed",
+	}, "\n") + "\n"
+
+	assert.Equal(t, expected, buffer.String())
+}
+
+func TestGitHubActionsGroupMarkers(t *testing.T) {
+	buffer := bytes.NewBuffer([]byte{})
+	logger := GetLoggerWithOptions(false, "[test] ", Options{Format: GitHubActionsFormat, Sinks: []*Sink{NewSink(buffer)}})
+
+	logger.WithGroup("client-1", func() {
+		logger.WithGroup("nested", func() {
+			logger.Infof("PONG")
+		})
+	})
+
+	expected := strings.Join([]string{
+		"::group::[test] client-1",
+		"[test] client-1",
+		"[test]   nested",
+		"[test]     PONG",
+		"::endgroup::",
+	}, "\n") + "\n"
+
+	assert.Equal(t, expected, buffer.String())
+}
+
+func TestJSONGroupMarkers(t *testing.T) {
+	buffer := bytes.NewBuffer([]byte{})
+	logger := GetLoggerWithOptions(false, "", Options{Format: JSONFormat, Sinks: []*Sink{NewSink(buffer)}})
+
+	logger.WithGroup("client-1", func() {
+		logger.Infof("PONG")
+	})
+
+	lines := strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+	if !assert.Len(t, lines, 3) {
+		t.FailNow()
+	}
+
+	assert.Contains(t, lines[0], `"level":"group_start"`)
+	assert.Contains(t, lines[0], `"message":"client-1"`)
+	assert.Contains(t, lines[1], `"groups":["client-1"]`)
+	assert.Contains(t, lines[2], `"level":"group_end"`)
+}
@@ -1,76 +1,245 @@
 package logger
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"regexp"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/fatih/color"
+	"github.com/make-core/tester-utils/color_policy"
+	"github.com/make-core/tester-utils/internal"
 )
 
-func colorize(colorToUse color.Attribute, fstring string, args ...any) []string {
-	var msg string
-
+func formatMessage(fstring string, args ...any) string {
 	if len(args) == 0 {
-		msg = fstring // Treat as plain string if no args
-	} else {
-		msg = fmt.Sprintf(fstring, args...) // Format if args are present
+		return fstring // Treat as plain string if no args
 	}
 
+	return fmt.Sprintf(fstring, args...) // Format if args are present
+}
+
+// colorize renders each line of the message in the given color, following Options.ColorPolicy
+func (l *Logger) colorize(colorToUse color.Attribute, fstring string, args ...any) []string {
+	msg := formatMessage(fstring, args...)
+
 	lines := strings.Split(msg, "\n")
 	colorizedLines := make([]string, len(lines))
 
+	c := color.New(colorToUse)
+	if l.options.ColorPolicy != "" {
+		if l.options.ColorPolicy.IsEnabled() {
+			c.EnableColor()
+		} else {
+			c.DisableColor()
+		}
+	}
+
 	for i, line := range lines {
-		colorizedLines[i] = color.New(colorToUse).SprintFunc()(line)
+		colorizedLines[i] = c.SprintFunc()(line)
 	}
 
 	return colorizedLines
 }
 
-func debugColorize(fstring string, args ...any) []string {
-	return colorize(color.FgCyan, fstring, args...)
+func (l *Logger) debugColorize(fstring string, args ...any) []string {
+	return l.colorize(color.FgCyan, fstring, args...)
+}
+
+func (l *Logger) infoColorize(fstring string, args ...any) []string {
+	return l.colorize(color.FgHiBlue, fstring, args...)
 }
 
-func infoColorize(fstring string, args ...any) []string {
-	return colorize(color.FgHiBlue, fstring, args...)
+func (l *Logger) successColorize(fstring string, args ...any) []string {
+	return l.colorize(color.FgHiGreen, fstring, args...)
 }
 
-func successColorize(fstring string, args ...any) []string {
-	return colorize(color.FgHiGreen, fstring, args...)
+func (l *Logger) errorColorize(fstring string, args ...any) []string {
+	return l.colorize(color.FgHiRed, fstring, args...)
 }
 
-func errorColorize(fstring string, args ...any) []string {
-	return colorize(color.FgHiRed, fstring, args...)
+func (l *Logger) warnColorize(fstring string, args ...any) []string {
+	return l.colorize(color.FgHiYellow, fstring, args...)
 }
 
-func yellowColorize(fstring string, args ...any) []string {
-	return colorize(color.FgYellow, fstring, args...)
+func (l *Logger) hintColorize(fstring string, args ...any) []string {
+	return l.colorize(color.FgHiMagenta, fstring, args...)
 }
 
-// globalLogMutex serializes all logging operations for this package
-var globalLogMutex sync.Mutex
+func (l *Logger) yellowColorize(fstring string, args ...any) []string {
+	return l.colorize(color.FgYellow, fstring, args...)
+}
 
-// Serializes logging using the package-level mutex
-type syncWriter struct {
+func plainColorize(fstring string, args ...any) []string {
+	return strings.Split(formatMessage(fstring, args...), "\n")
+}
+
+// Sink is a destination for log output. Writes to a Sink are serialized, so any number of loggers can share one.
+type Sink struct {
+	mutex  sync.Mutex
 	writer io.Writer
+
+	// buffer holds writes while buffering is enabled, see StartBuffering
+	buffer *bytes.Buffer
 }
 
-func (s syncWriter) Write(p []byte) (n int, err error) {
-	globalLogMutex.Lock()
-	defer globalLogMutex.Unlock()
+// NewSink returns a Sink that writes to writer.
+func NewSink(writer io.Writer) *Sink {
+	return &Sink{writer: writer}
+}
+
+func (s *Sink) Write(p []byte) (n int, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.buffer != nil {
+		return s.buffer.Write(p)
+	}
+
 	n, err = s.writer.Write(p)
 	return n, err
 }
 
+// StartBuffering holds all writes to this sink in memory until StopBuffering is called.
+func (s *Sink) StartBuffering() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.buffer == nil {
+		s.buffer = bytes.NewBuffer([]byte{})
+	}
+}
+
+// StopBuffering stops holding writes in memory. If shouldFlush is true, writes held so far are written out, otherwise
+// they're discarded.
+func (s *Sink) StopBuffering(shouldFlush bool) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	buffer := s.buffer
+	s.buffer = nil
+
+	if buffer == nil || !shouldFlush {
+		return nil
+	}
+
+	_, err := s.writer.Write(buffer.Bytes())
+	return err
+}
+
+// stdoutWriter looks up os.Stdout on every write, so that output is captured when os.Stdout is swapped (see stdio_mocker)
+type stdoutWriter struct{}
+
+func (w stdoutWriter) Write(p []byte) (n int, err error) {
+	return os.Stdout.Write(p)
+}
+
+// StdoutSink is the Sink used by loggers that don't specify any Options.Sinks.
+var StdoutSink = NewSink(stdoutWriter{})
+
+// multiSinkWriter writes to all sinks, continuing past errors so that one broken sink doesn't affect the others
+type multiSinkWriter struct {
+	sinks []*Sink
+}
+
+func (w multiSinkWriter) Write(p []byte) (n int, err error) {
+	for _, sink := range w.sinks {
+		if _, sinkErr := sink.Write(p); sinkErr != nil && err == nil {
+			err = sinkErr
+		}
+	}
+
+	return len(p), err
+}
+
+// Format controls how log lines are rendered
+type Format string
+
+const (
+	// TextFormat renders colorized, human-readable lines. This is the default.
+	TextFormat Format = "text"
+
+	// JSONFormat renders one JSON object per line, for consumption by other programs.
+	JSONFormat Format = "json"
+
+	// GitHubActionsFormat renders the same lines as TextFormat, but emits fold markers for log groups.
+	GitHubActionsFormat Format = "github-actions"
+)
+
+// ParseFormat converts a user-provided value (like "json") into a Format. An empty value is treated as TextFormat.
+func ParseFormat(value string) (Format, error) {
+	switch Format(value) {
+	case "", TextFormat:
+		return TextFormat, nil
+	case JSONFormat:
+		return JSONFormat, nil
+	case GitHubActionsFormat:
+		return GitHubActionsFormat, nil
+	default:
+		return "", fmt.Errorf("unknown log format %q, expected one of: text, json, github-actions", value)
+	}
+}
+
+// Options holds configuration that is shared by a logger and all its clones.
+type Options struct {
+	// Format controls how log lines are rendered. Defaults to TextFormat.
+	Format Format
+
+	// Sinks are the destinations that logs are written to. Defaults to StdoutSink.
+	Sinks []*Sink
+
+	// Stopwatch, if set, is used to prefix each line with the time elapsed since the stopwatch was last reset.
+	Stopwatch *Stopwatch
+
+	// Redactor is used to replace sensitive or unstable values in all messages. Loggers that share a Redactor also share
+	// redaction rules registered using Logger.RedactLiteral and Logger.RedactPattern. Defaults to a new Redactor.
+	Redactor *Redactor
+
+	// ColorPolicy overrides the global color setting (see color_policy.Apply) for this logger. Defaults to the global
+	// setting.
+	ColorPolicy color_policy.ColorPolicy
+
+	// LogGroups tracks the log groups that are currently open, see Logger.BeginGroup. Loggers that share LogGroups nest
+	// their lines in the same groups, like a test case's logger and the logger that relays the program's output. Defaults
+	// to new LogGroups.
+	LogGroups *LogGroups
+
+	// CollapseGroupsWithoutErrors hides the contents of log groups that don't contain any error logs. Only applies to
+	// TextFormat.
+	CollapseGroupsWithoutErrors bool
+
+	// DeduplicateHints ensures that a hint is emitted only once by a logger and its clones, even if Hintf is called
+	// repeatedly (in a loop, for example).
+	DeduplicateHints bool
+}
+
+// GetSinks returns the sinks that loggers created with these Options write to.
+func (o Options) GetSinks() []*Sink {
+	if len(o.Sinks) == 0 {
+		return []*Sink{StdoutSink}
+	}
+
+	return o.Sinks
+}
+
+func (o Options) getWriter() io.Writer {
+	return multiSinkWriter{sinks: o.GetSinks()}
+}
+
 // Logger is a wrapper around log.Logger with the following features:
 //   - Supports a prefix
-//   - Adds colors to the output
+//   - Adds colors to the output (see color_policy)
 //   - Debug mode (all logs, debug and above)
 //   - Quiet mode (only critical logs)
-//   - Serialized writes for all loggers in this package
+//   - Serialized writes for all loggers that share a Sink
+//   - Text or JSON output (see Options.Format)
 type Logger struct {
 	// IsDebug is used to determine whether to emit debug logs.
 	IsDebug bool
@@ -84,18 +253,166 @@ type Logger struct {
 	// secondaryPrefixes is a slice of prefixes that are printed after Logger.prefix
 	secondaryPrefixes []string
 
+	options Options
+
+	// hints is shared between a logger and its clones
+	hints *hintCollector
+
 	logger log.Logger
 }
 
+// Stopwatch measures the time elapsed since it was last reset. Loggers that share a Stopwatch (via Options.Stopwatch)
+// report elapsed times relative to the same instant, usually the start of a stage.
+type Stopwatch struct {
+	mutex     sync.Mutex
+	startedAt time.Time
+}
+
+// NewStopwatch returns a Stopwatch that starts counting immediately.
+func NewStopwatch() *Stopwatch {
+	return &Stopwatch{startedAt: time.Now()}
+}
+
+// Reset restarts the stopwatch from zero. It's a no-op on a nil Stopwatch.
+func (s *Stopwatch) Reset() {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.startedAt = time.Now()
+}
+
+// Elapsed returns the time since the stopwatch was last reset.
+//
+// When recording or evaluating fixtures this is always zero, since real timings would make fixtures unstable.
+func (s *Stopwatch) Elapsed() time.Duration {
+	if internal.IsRecordingOrEvaluatingFixtures {
+		return 0
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return time.Since(s.startedAt)
+}
+
+// Redactor replaces values like tokens, temporary paths or PIDs in log messages with placeholders. This hides secrets from
+// users and keeps fixtures stable.
+type Redactor struct {
+	mutex sync.Mutex
+	rules []redactionRule
+}
+
+type redactionRule struct {
+	pattern     *regexp.Regexp
+	placeholder string
+}
+
+// NewRedactor returns a Redactor with no rules.
+func NewRedactor() *Redactor {
+	return &Redactor{}
+}
+
+// AddLiteral replaces all occurrences of value with placeholder. Empty values are ignored.
+func (r *Redactor) AddLiteral(value string, placeholder string) {
+	if value == "" {
+		return
+	}
+
+	r.AddPattern(regexp.MustCompile(regexp.QuoteMeta(value)), placeholder)
+}
+
+// AddPattern replaces all matches of pattern with placeholder. The placeholder can reference submatches, like "$1".
+func (r *Redactor) AddPattern(pattern *regexp.Regexp, placeholder string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.rules = append(r.rules, redactionRule{pattern: pattern, placeholder: placeholder})
+}
+
+// Redact applies all rules to msg, in the order they were added.
+func (r *Redactor) Redact(msg string) string {
+	if r == nil {
+		return msg
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, rule := range r.rules {
+		msg = rule.pattern.ReplaceAllString(msg, rule.placeholder)
+	}
+
+	return msg
+}
+
+// hintCollector records hints emitted by a logger and all its clones
+type hintCollector struct {
+	mutex sync.Mutex
+	hints []string
+}
+
+// add records hint, returning false if it was already recorded
+func (c *hintCollector) add(hint string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if slices.Contains(c.hints, hint) {
+		return false
+	}
+
+	c.hints = append(c.hints, hint)
+	return true
+}
+
+func (c *hintCollector) getHints() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return slices.Clone(c.hints)
+}
+
+// jsonLogEntry is a single line emitted when using JSONFormat
+type jsonLogEntry struct {
+	Level             string   `json:"level"`
+	Prefix            string   `json:"prefix"`
+	SecondaryPrefixes []string `json:"secondary_prefixes"`
+	Timestamp         string   `json:"timestamp"`
+	ElapsedMs         *int64   `json:"elapsed_ms,omitempty"`
+	Groups            []string `json:"groups,omitempty"`
+	Message           string   `json:"message"`
+}
+
 // GetLogger Returns a logger.
 func GetLogger(isDebug bool, prefix string) *Logger {
-	color.NoColor = false
-	coloredPrefix := yellowColorize("%s", prefix)[0]
-	return &Logger{
-		logger:  *log.New(syncWriter{writer: os.Stdout}, coloredPrefix, 0),
+	return GetLoggerWithOptions(isDebug, prefix, Options{})
+}
+
+// GetLoggerWithOptions returns a logger that uses the given Options.
+func GetLoggerWithOptions(isDebug bool, prefix string, options Options) *Logger {
+	if options.Format == "" {
+		options.Format = TextFormat
+	}
+
+	if options.Redactor == nil {
+		options.Redactor = NewRedactor()
+	}
+
+	if options.LogGroups == nil {
+		options.LogGroups = NewLogGroups()
+	}
+
+	l := &Logger{
+		logger:  *log.New(options.getWriter(), "", 0),
 		IsDebug: isDebug,
 		prefix:  prefix,
+		options: options,
+		hints:   &hintCollector{},
 	}
+	l.updateLoggerPrefix()
+
+	return l
 }
 
 // Clone clones a given logger
@@ -103,15 +420,14 @@ func (l *Logger) Clone() *Logger {
 	secondaryPrefixesCopy := make([]string, len(l.secondaryPrefixes))
 	copy(secondaryPrefixesCopy, l.secondaryPrefixes)
 
-	newSyncWriter := syncWriter{writer: os.Stdout}
-	newColoredPrefix := yellowColorize("%s", l.prefix)[0]
-
 	cloned := &Logger{
-		logger:            *log.New(newSyncWriter, newColoredPrefix, 0),
+		logger:            *log.New(l.options.getWriter(), "", 0),
 		IsDebug:           l.IsDebug,
 		IsQuiet:           l.IsQuiet,
 		prefix:            l.prefix,
 		secondaryPrefixes: secondaryPrefixesCopy,
+		options:           l.options,
+		hints:             l.hints,
 	}
 	cloned.updateLoggerPrefix()
 
@@ -152,13 +468,13 @@ func (l *Logger) ResetSecondaryPrefixes() {
 // updateLoggerPrefix updates the logger's prefix based on all secondary prefixes
 func (l *Logger) updateLoggerPrefix() {
 	if len(l.secondaryPrefixes) == 0 {
-		l.logger.SetPrefix(yellowColorize("%s", l.prefix)[0])
+		l.logger.SetPrefix(l.yellowColorize("%s", l.prefix)[0])
 	} else {
 		fullPrefix := l.prefix
 		for _, secondaryPrefix := range l.secondaryPrefixes {
 			fullPrefix += fmt.Sprintf("[%s] ", secondaryPrefix)
 		}
-		l.logger.SetPrefix(yellowColorize("%s", fullPrefix)[0])
+		l.logger.SetPrefix(l.yellowColorize("%s", fullPrefix)[0])
 	}
 }
 
@@ -189,14 +505,132 @@ func (l *Logger) WithAdditionalSecondaryPrefix(prefix string, fn func()) {
 
 // GetQuietLogger Returns a logger that only emits critical logs. Useful for anti-cheat stages.
 func GetQuietLogger(prefix string) *Logger {
-	color.NoColor = false
-	coloredPrefix := yellowColorize("%s", prefix)[0]
-	return &Logger{
-		logger:  *log.New(syncWriter{writer: os.Stdout}, coloredPrefix, 0),
+	return GetQuietLoggerWithOptions(prefix, Options{})
+}
+
+// GetQuietLoggerWithOptions returns a quiet logger that uses the given Options.
+func GetQuietLoggerWithOptions(prefix string, options Options) *Logger {
+	if options.Format == "" {
+		options.Format = TextFormat
+	}
+
+	if options.Redactor == nil {
+		options.Redactor = NewRedactor()
+	}
+
+	if options.LogGroups == nil {
+		options.LogGroups = NewLogGroups()
+	}
+
+	l := &Logger{
+		logger:  *log.New(options.getWriter(), "", 0),
 		IsDebug: false,
 		IsQuiet: true,
 		prefix:  prefix,
+		options: options,
+		hints:   &hintCollector{},
+	}
+	l.updateLoggerPrefix()
+
+	return l
+}
+
+// RedactLiteral replaces all occurrences of value with placeholder in messages logged after this call.
+//
+// Redaction rules are shared with clones of this logger, and with any loggers that share the same Options.Redactor.
+func (l *Logger) RedactLiteral(value string, placeholder string) {
+	l.options.Redactor.AddLiteral(value, placeholder)
+}
+
+// RedactPattern replaces all matches of pattern with placeholder in messages logged after this call.
+//
+// Redaction rules are shared with clones of this logger, and with any loggers that share the same Options.Redactor.
+func (l *Logger) RedactPattern(pattern *regexp.Regexp, placeholder string) {
+	l.options.Redactor.AddPattern(pattern, placeholder)
+}
+
+// PrintBlankLine prints an empty line without any prefixes, used to visually separate stages.
+//
+// This is a no-op when using JSONFormat.
+func (l *Logger) PrintBlankLine() {
+	if l.options.Format == JSONFormat {
+		return
+	}
+
+	l.logger.Writer().Write([]byte("\n"))
+}
+
+// write emits msg at the given level, one line at a time for TextFormat and as a single object for JSONFormat.
+func (l *Logger) write(level string, colorizeFunc func(string, ...any) []string, msg string) {
+	msg = l.options.Redactor.Redact(msg)
+
+	l.options.LogGroups.mutex.Lock()
+	defer l.options.LogGroups.mutex.Unlock()
+
+	if l.options.Format == JSONFormat {
+		l.writeJSON(level, msg)
+		return
+	}
+
+	linePrefix := l.getLinePrefix()
+	isError := level == "error" || level == "critical"
+
+	for _, line := range colorizeFunc("%s", msg) {
+		l.writeLine(linePrefix+line+"\n", isError)
+	}
+}
+
+// withLabel prepends label (like "Warning: ") to msg for text-based formats. JSON entries already include the level.
+func (l *Logger) withLabel(label string, msg string) string {
+	if l.options.Format == JSONFormat {
+		return msg
+	}
+
+	return label + msg
+}
+
+// getLinePrefix returns everything that's printed before a line's contents when using a text-based Format.
+//
+// log.Logger can only prepend a fixed prefix, so lines are assembled manually instead of using log.Logger.Println.
+// Must be called with LogGroups.mutex held.
+func (l *Logger) getLinePrefix() string {
+	linePrefix := l.logger.Prefix()
+	if l.options.Stopwatch != nil {
+		linePrefix = l.yellowColorize("[%5dms] ", l.options.Stopwatch.Elapsed().Milliseconds())[0] + linePrefix
+	}
+
+	return linePrefix + strings.Repeat("  ", len(l.options.LogGroups.stack))
+}
+
+// writeJSON must be called with LogGroups.mutex held
+func (l *Logger) writeJSON(level string, msg string) {
+	secondaryPrefixes := make([]string, len(l.secondaryPrefixes))
+	copy(secondaryPrefixes, l.secondaryPrefixes)
+
+	entry := jsonLogEntry{
+		Level:             level,
+		Prefix:            strings.TrimSpace(l.prefix),
+		SecondaryPrefixes: secondaryPrefixes,
+		Timestamp:         time.Now().UTC().Format(time.RFC3339Nano),
+		Message:           msg,
 	}
+
+	for _, group := range l.options.LogGroups.stack {
+		entry.Groups = append(entry.Groups, group.title)
+	}
+
+	if l.options.Stopwatch != nil {
+		elapsedMs := l.options.Stopwatch.Elapsed().Milliseconds()
+		entry.ElapsedMs = &elapsedMs
+	}
+
+	encoded, err := json.Marshal(entry)
+	if err != nil {
+		panic(fmt.Sprintf("CodeCrafters Internal Error - failed to encode log entry: %s", err))
+	}
+
+	// Bypass log.Logger so that the prefix isn't prepended
+	l.logger.Writer().Write(append(encoded, '\n'))
 }
 
 func (l *Logger) Successf(fstring string, args ...any) {
@@ -204,18 +638,15 @@ func (l *Logger) Successf(fstring string, args ...any) {
 		return
 	}
 
-	for _, line := range successColorize(fstring, args...) {
-		l.logger.Println(line)
-	}
+	l.write("success", l.successColorize, formatMessage(fstring, args...))
 }
 
 func (l *Logger) Successln(msg string) {
 	if l.IsQuiet {
 		return
 	}
-	for _, line := range successColorize("%s", msg) {
-		l.logger.Println(line)
-	}
+
+	l.write("success", l.successColorize, msg)
 }
 
 func (l *Logger) Infof(fstring string, args ...any) {
@@ -223,9 +654,7 @@ func (l *Logger) Infof(fstring string, args ...any) {
 		return
 	}
 
-	for _, line := range infoColorize(fstring, args...) {
-		l.logger.Println(line)
-	}
+	l.write("info", l.infoColorize, formatMessage(fstring, args...))
 }
 
 func (l *Logger) Infoln(msg string) {
@@ -233,8 +662,60 @@ func (l *Logger) Infoln(msg string) {
 		return
 	}
 
-	for _, line := range infoColorize("%s", msg) {
-		l.logger.Println(line)
+	l.write("info", l.infoColorize, msg)
+}
+
+// Warnf is to be used for things that don't fail a test, but that the user should know about.
+func (l *Logger) Warnf(fstring string, args ...any) {
+	if l.IsQuiet {
+		return
+	}
+
+	l.write("warning", l.warnColorize, l.withLabel("Warning: ", formatMessage(fstring, args...)))
+}
+
+func (l *Logger) Warnln(msg string) {
+	if l.IsQuiet {
+		return
+	}
+
+	l.write("warning", l.warnColorize, l.withLabel("Warning: ", msg))
+}
+
+// Hintf is to be used for suggestions that might help the user fix a failure. Example: "Did you forget \r\n?"
+func (l *Logger) Hintf(fstring string, args ...any) {
+	l.hint(formatMessage(fstring, args...))
+}
+
+func (l *Logger) Hintln(msg string) {
+	l.hint(msg)
+}
+
+func (l *Logger) hint(msg string) {
+	if l.IsQuiet {
+		return
+	}
+
+	if isNew := l.hints.add(msg); !isNew && l.options.DeduplicateHints {
+		return
+	}
+
+	l.write("hint", l.hintColorize, l.withLabel("Hint: ", msg))
+}
+
+// CollectedHints returns all unique hints emitted by this logger and its clones, in the order they were first emitted.
+func (l *Logger) CollectedHints() []string {
+	return l.hints.getHints()
+}
+
+// ReplayHints emits all collected hints again. Useful after a failure, since hints might've scrolled past.
+func (l *Logger) ReplayHints() {
+	if l.IsQuiet {
+		return
+	}
+
+	for _, hint := range l.CollectedHints() {
+		l.write("hint", l.hintColorize, l.withLabel("Hint: ", hint))
 	}
 }
 
@@ -244,9 +725,7 @@ func (l *Logger) Criticalf(fstring string, args ...any) {
 		panic("Critical is only for quiet loggers")
 	}
 
-	for _, line := range errorColorize(fstring, args...) {
-		l.logger.Println(line)
-	}
+	l.write("critical", l.errorColorize, formatMessage(fstring, args...))
 }
 
 // Criticalln is to be used only in anti-cheat stages
@@ -255,9 +734,7 @@ func (l *Logger) Criticalln(msg string) {
 		panic("Critical is only for quiet loggers")
 	}
 
-	for _, line := range errorColorize("%s", msg) {
-		l.logger.Println(line)
-	}
+	l.write("critical", l.errorColorize, msg)
 }
 
 func (l *Logger) Errorf(fstring string, args ...any) {
@@ -265,9 +742,7 @@ func (l *Logger) Errorf(fstring string, args ...any) {
 		return
 	}
 
-	for _, line := range errorColorize(fstring, args...) {
-		l.logger.Println(line)
-	}
+	l.write("error", l.errorColorize, formatMessage(fstring, args...))
 }
 
 func (l *Logger) Errorln(msg string) {
@@ -275,9 +750,7 @@ func (l *Logger) Errorln(msg string) {
 		return
 	}
 
-	for _, line := range errorColorize("%s", msg) {
-		l.logger.Println(line)
-	}
+	l.write("error", l.errorColorize, msg)
 }
 
 func (l *Logger) Debugf(fstring string, args ...any) {
@@ -285,9 +758,7 @@ func (l *Logger) Debugf(fstring string, args ...any) {
 		return
 	}
 
-	for _, line := range debugColorize(fstring, args...) {
-		l.logger.Println(line)
-	}
+	l.write("debug", l.debugColorize, formatMessage(fstring, args...))
 }
 
 func (l *Logger) Debugln(msg string) {
@@ -295,23 +766,13 @@ func (l *Logger) Debugln(msg string) {
 		return
 	}
 
-	for _, line := range debugColorize("%s", msg) {
-		l.logger.Println(line)
-	}
+	l.write("debug", l.debugColorize, msg)
 }
 
 func (l *Logger) Plainf(fstring string, args ...any) {
-	formattedString := fmt.Sprintf(fstring, args...)
-
-	for line := range strings.SplitSeq(formattedString, "\n") {
-		l.logger.Println(line)
-	}
+	l.write("plain", plainColorize, fmt.Sprintf(fstring, args...))
 }
 
 func (l *Logger) Plainln(msg string) {
-	lines := strings.SplitSeq(msg, "\n")
-
-	for line := range lines {
-		l.logger.Println(line)
-	}
+	l.write("plain", plainColorize, msg)
 }
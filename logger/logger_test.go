@@ -0,0 +1,231 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/make-core/tester-utils/color_policy"
+	"github.com/make-core/tester-utils/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	// Colors are tested in bytes_diff_visualizer, plain output keeps these tests readable
+	color.NoColor = true
+
+	os.Exit(m.Run())
+}
+
+func TestWritesToSink(t *testing.T) {
+	buffer := bytes.NewBuffer([]byte{})
+	logger := GetLoggerWithOptions(false, "[test] ", Options{Sinks: []*Sink{NewSink(buffer)}})
+
+	logger.Infof("hello %s", "world")
+	logger.Debugf("not shown")
+
+	expected := logger.yellowColorize("%s", "[test] ")[0] + logger.infoColorize("%s", "hello world")[0] + "\n"
+	assert.Equal(t, expected, buffer.String())
+}
+
+func TestWritesToMultipleSinks(t *testing.T) {
+	buffer1 := bytes.NewBuffer([]byte{})
+	buffer2 := bytes.NewBuffer([]byte{})
+	logger := GetLoggerWithOptions(false, "", Options{Sinks: []*Sink{NewSink(buffer1), NewSink(buffer2)}})
+
+	logger.Plainln("hello")
+	logger.Clone().Plainln("from clone")
+
+	assert.Equal(t, buffer1.String(), buffer2.String())
+	assert.Contains(t, buffer1.String(), "hello\n")
+	assert.Contains(t, buffer1.String(), "from clone\n")
+}
+
+func TestSerializesWritesToSharedSink(t *testing.T) {
+	buffer := bytes.NewBuffer([]byte{})
+	sink := NewSink(buffer)
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		logger := GetLoggerWithOptions(false, "[test] ", Options{Sinks: []*Sink{sink}})
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := range 100 {
+				logger.Plainf("logger-%d line-%d", i, j)
+			}
+		}()
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+	assert.Len(t, lines, 1000)
+
+	coloredPrefix := GetLogger(false, "").yellowColorize("%s", "[test] ")[0]
+	for _, line := range lines {
+		assert.Regexp(t, `^logger-\d+ line-\d+$`, strings.TrimPrefix(line, coloredPrefix))
+	}
+}
+
+func TestJSONFormat(t *testing.T) {
+	buffer := bytes.NewBuffer([]byte{})
+	logger := GetLoggerWithOptions(true, "[test] ", Options{Format: JSONFormat, Sinks: []*Sink{NewSink(buffer)}})
+
+	logger.WithAdditionalSecondaryPrefix("client-1", func() {
+		logger.Debugf("line 1\nline 2")
+	})
+	logger.PrintBlankLine()
+	logger.Errorln("failed")
+
+	lines := strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+	if !assert.Len(t, lines, 2) {
+		t.FailNow()
+	}
+
+	entries := []jsonLogEntry{}
+	for _, line := range lines {
+		entry := jsonLogEntry{}
+		if !assert.NoError(t, json.Unmarshal([]byte(line), &entry), fmt.Sprintf("line: %s", line)) {
+			t.FailNow()
+		}
+
+		assert.NotEmpty(t, entry.Timestamp)
+		entries = append(entries, entry)
+	}
+
+	assert.Equal(t, "debug", entries[0].Level)
+	assert.Equal(t, "[test]", entries[0].Prefix)
+	assert.Equal(t, []string{"client-1"}, entries[0].SecondaryPrefixes)
+	assert.Equal(t, "line 1\nline 2", entries[0].Message)
+
+	assert.Equal(t, "error", entries[1].Level)
+	assert.Equal(t, []string{}, entries[1].SecondaryPrefixes)
+	assert.Equal(t, "failed", entries[1].Message)
+}
+
+func TestWarningsAndHints(t *testing.T) {
+	buffer := bytes.NewBuffer([]byte{})
+	logger := GetLoggerWithOptions(false, "", Options{Format: JSONFormat, Sinks: []*Sink{NewSink(buffer)}})
+
+	logger.Warnf("took %dms", 500)
+	logger.Hintln("did you forget \\r\\n?")
+	logger.Clone().Hintln("did you forget \\r\\n?")
+
+	assert.Equal(t, []string{"did you forget \\r\\n?"}, logger.CollectedHints())
+	assert.Equal(t, 3, strings.Count(buffer.String(), "\n"))
+	assert.Contains(t, buffer.String(), `"level":"warning"`)
+	assert.Contains(t, buffer.String(), `"message":"took 500ms"`)
+	assert.Contains(t, buffer.String(), `"level":"hint"`)
+	assert.Contains(t, buffer.String(), `"message":"did you forget \\r\\n?"`)
+}
+
+func TestDeduplicatesHints(t *testing.T) {
+	buffer := bytes.NewBuffer([]byte{})
+	logger := GetLoggerWithOptions(false, "", Options{DeduplicateHints: true, Sinks: []*Sink{NewSink(buffer)}})
+
+	for i := range 3 {
+		logger.Hintf("check key %d", i%2)
+	}
+	logger.Clone().Hintf("check key %d", 0)
+
+	assert.Equal(t, "Hint: check key 0\nHint: check key 1\n", buffer.String())
+	assert.Equal(t, []string{"check key 0", "check key 1"}, logger.CollectedHints())
+
+	buffer.Reset()
+	logger.ReplayHints()
+	assert.Equal(t, "Hint: check key 0\nHint: check key 1\n", buffer.String())
+}
+
+func TestQuietLoggerSkipsWarningsAndHints(t *testing.T) {
+	buffer := bytes.NewBuffer([]byte{})
+	logger := GetQuietLoggerWithOptions("", Options{Sinks: []*Sink{NewSink(buffer)}})
+
+	logger.Warnf("warning")
+	logger.Hintf("hint")
+	logger.ReplayHints()
+
+	assert.Equal(t, "", buffer.String())
+	assert.Empty(t, logger.CollectedHints())
+}
+
+func TestStopwatchPrefixesElapsedTime(t *testing.T) {
+	buffer := bytes.NewBuffer([]byte{})
+	stopwatch := NewStopwatch()
+	logger := GetLoggerWithOptions(false, "[test] ", Options{Stopwatch: stopwatch, Sinks: []*Sink{NewSink(buffer)}})
+
+	time.Sleep(20 * time.Millisecond)
+	logger.Infof("after sleep")
+	assert.Regexp(t, `^\[ +(\d+)ms\] \[test\] after sleep\n$`, buffer.String())
+	assert.NotEqual(t, "[    0ms] [test] after sleep\n", buffer.String())
+
+	buffer.Reset()
+	internal.IsRecordingOrEvaluatingFixtures = true
+	defer func() { internal.IsRecordingOrEvaluatingFixtures = false }()
+
+	logger.Clone().Infof("line 1\nline 2")
+	assert.Equal(t, "[    0ms] [test] line 1\n[    0ms] [test] line 2\n", buffer.String())
+}
+
+func TestRedaction(t *testing.T) {
+	buffer := bytes.NewBuffer([]byte{})
+	logger := GetLoggerWithOptions(false, "", Options{Sinks: []*Sink{NewSink(buffer)}})
+
+	logger.RedactLiteral("/tmp/redis-123", "<TMP_DIR>")
+	logger.RedactPattern(regexp.MustCompile(`pid=\d+`), "pid=<PID>")
+
+	logger.Infof("dir: /tmp/redis-123/dump.rdb, pid=4242")
+	logger.Clone().Plainln("cloned: /tmp/redis-123")
+
+	assert.Equal(t, "dir: <TMP_DIR>/dump.rdb, pid=<PID>\ncloned: <TMP_DIR>\n", buffer.String())
+}
+
+func TestRedactionIsSharedViaOptions(t *testing.T) {
+	buffer := bytes.NewBuffer([]byte{})
+	options := Options{Redactor: NewRedactor(), Sinks: []*Sink{NewSink(buffer)}}
+
+	testerLogger := GetLoggerWithOptions(false, "", options)
+	programLogger := GetLoggerWithOptions(false, "", options)
+	unrelatedLogger := GetLoggerWithOptions(false, "", Options{Sinks: []*Sink{NewSink(buffer)}})
+
+	testerLogger.RedactLiteral("secret-token", "<TOKEN>")
+	programLogger.Plainln("token: secret-token")
+	unrelatedLogger.Plainln("token: secret-token")
+
+	assert.Equal(t, "token: <TOKEN>\ntoken: secret-token\n", buffer.String())
+}
+
+func TestSinkBuffering(t *testing.T) {
+	buffer := bytes.NewBuffer([]byte{})
+	sink := NewSink(buffer)
+	logger := GetLoggerWithOptions(false, "", Options{Sinks: []*Sink{sink}})
+
+	sink.StartBuffering()
+	logger.Plainln("discarded")
+	assert.NoError(t, sink.StopBuffering(false))
+
+	sink.StartBuffering()
+	logger.Plainln("flushed")
+	assert.Equal(t, "", buffer.String())
+	assert.NoError(t, sink.StopBuffering(true))
+
+	logger.Plainln("unbuffered")
+	assert.Equal(t, "flushed\nunbuffered\n", buffer.String())
+}
+
+func TestColorPolicyOverridesGlobalSetting(t *testing.T) {
+	buffer := bytes.NewBuffer([]byte{})
+	logger := GetLoggerWithOptions(false, "", Options{ColorPolicy: color_policy.Always, Sinks: []*Sink{NewSink(buffer)}})
+
+	logger.Infof("hello")
+
+	assert.Contains(t, buffer.String(), "\x1b[")
+}
@@ -0,0 +1,135 @@
+package random
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Theme string
+
+const (
+	ThemeFruits  Theme = "fruits"
+	ThemeAnimals Theme = "animals"
+	ThemeColors  Theme = "colors"
+	ThemeVerbs   Theme = "verbs"
+)
+
+// Themes lists every available theme
+var Themes = []Theme{ThemeFruits, ThemeAnimals, ThemeColors, ThemeVerbs}
+
+// dictionaries holds the words for each theme. Words are lowercase ASCII and unique across all themes.
+//
+// RandomWord, RandomWords & RandomString use randomWords (not these), so that their output stays the same.
+var dictionaries = map[Theme][]string{
+	ThemeFruits: {
+		"apple", "orange", "banana", "pear", "grape", "pineapple", "mango", "strawberry", "raspberry", "blueberry",
+		"apricot", "avocado", "blackberry", "cherry", "coconut", "cranberry", "date", "dragonfruit", "fig", "grapefruit",
+		"guava", "kiwi", "lemon", "lime", "lychee", "mandarin", "melon", "nectarine", "papaya", "passionfruit",
+		"peach", "persimmon", "plum", "pomegranate", "quince", "tangerine", "watermelon", "gooseberry", "mulberry", "olive",
+	},
+	ThemeAnimals: {
+		"alpaca", "badger", "beaver", "bison", "camel", "cheetah", "cobra", "coyote", "crane", "dolphin",
+		"donkey", "eagle", "falcon", "ferret", "flamingo", "gazelle", "gecko", "giraffe", "gorilla", "hedgehog",
+		"heron", "hyena", "iguana", "jaguar", "kangaroo", "koala", "lemur", "leopard", "llama", "lynx",
+		"meerkat", "moose", "narwhal", "ocelot", "otter", "panda", "pelican", "penguin", "raccoon", "walrus",
+	},
+	ThemeColors: {
+		"amber", "aqua", "azure", "beige", "black", "blue", "bronze", "brown", "burgundy", "charcoal",
+		"coral", "crimson", "cyan", "emerald", "gold", "gray", "green", "indigo", "ivory", "jade",
+		"khaki", "lavender", "magenta", "maroon", "mauve", "navy", "ochre", "pink", "purple", "red",
+		"ruby", "saffron", "salmon", "scarlet", "sepia", "silver", "teal", "turquoise", "violet", "yellow",
+	},
+	ThemeVerbs: {
+		"build", "carry", "catch", "climb", "cook", "dance", "dig", "draw", "drive", "explore",
+		"fetch", "fly", "gather", "glide", "hide", "hop", "jump", "juggle", "kick", "knit",
+		"laugh", "listen", "march", "paint", "play", "pull", "push", "read", "ride", "run",
+		"sail", "sing", "skate", "sleep", "swim", "throw", "travel", "wander", "whistle", "write",
+	},
+}
+
+// WordsForTheme returns a copy of the words for theme. It panics if the theme doesn't exist.
+func WordsForTheme(theme Theme) []string {
+	words, ok := dictionaries[theme]
+	if !ok {
+		panic(fmt.Sprintf("unknown theme: %q", theme))
+	}
+
+	return append([]string{}, words...)
+}
+
+// allWords returns the words from every theme
+func allWords() []string {
+	words := []string{}
+	for _, theme := range Themes {
+		words = append(words, dictionaries[theme]...)
+	}
+
+	return words
+}
+
+// RandomWordFromTheme returns a random word from the given theme.
+func RandomWordFromTheme(theme Theme) string {
+	return defaultSource.RandomWordFromTheme(theme)
+}
+
+// RandomWordFromTheme returns a random word from the given theme.
+func (s *Source) RandomWordFromTheme(theme Theme) string {
+	return RandomElementFromArrayWithSource(s, WordsForTheme(theme))
+}
+
+// UniqueRandomWordsFromTheme returns n distinct random words from the given theme.
+// It panics if n is greater than the number of words in the theme.
+func UniqueRandomWordsFromTheme(theme Theme, n int) []string {
+	return defaultSource.UniqueRandomWordsFromTheme(theme, n)
+}
+
+// UniqueRandomWordsFromTheme returns n distinct random words from the given theme.
+// It panics if n is greater than the number of words in the theme.
+func (s *Source) UniqueRandomWordsFromTheme(theme Theme, n int) []string {
+	return uniqueElements(s, WordsForTheme(theme), n)
+}
+
+// UniqueRandomWords returns n distinct random words from all themes.
+// It panics if n is greater than the total number of words.
+func UniqueRandomWords(n int) []string {
+	return defaultSource.UniqueRandomWords(n)
+}
+
+// UniqueRandomWords returns n distinct random words from all themes.
+// It panics if n is greater than the total number of words.
+func (s *Source) UniqueRandomWords(n int) []string {
+	return uniqueElements(s, allWords(), n)
+}
+
+// UniqueRandomStrings returns n distinct random strings, each made of 6 words from all themes.
+func UniqueRandomStrings(n int) []string {
+	return defaultSource.UniqueRandomStrings(n)
+}
+
+// UniqueRandomStrings returns n distinct random strings, each made of 6 words from all themes.
+func (s *Source) UniqueRandomStrings(n int) []string {
+	words := allWords()
+
+	randomStrings := make([]string, 0, n)
+	seen := make(map[string]bool, n)
+
+	for len(randomStrings) < n {
+		randomString := strings.Join(RandomElementsFromArrayWithSource(s, words, 6), " ")
+		if seen[randomString] {
+			continue
+		}
+
+		seen[randomString] = true
+		randomStrings = append(randomStrings, randomString)
+	}
+
+	return randomStrings
+}
+
+func uniqueElements(s *Source, elements []string, n int) []string {
+	if n > len(elements) {
+		panic(fmt.Sprintf("can't generate %d unique words, only %d are available", n, len(elements)))
+	}
+
+	return RandomElementsFromArrayWithSource(s, elements, n)
+}
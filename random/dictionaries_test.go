@@ -0,0 +1,79 @@
+package random
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDictionaries(t *testing.T) {
+	seen := map[string]Theme{}
+
+	for _, theme := range Themes {
+		words := WordsForTheme(theme)
+		assert.GreaterOrEqual(t, len(words), 40, theme)
+
+		for _, word := range words {
+			assert.Regexp(t, regexp.MustCompile(`^[a-z]+$`), word)
+
+			if otherTheme, ok := seen[word]; ok {
+				t.Errorf("%q is in both %s and %s", word, otherTheme, theme)
+			}
+
+			seen[word] = theme
+		}
+	}
+
+	assert.Panics(t, func() { WordsForTheme("planets") })
+}
+
+func TestRandomWordsFromTheme(t *testing.T) {
+	Init()
+
+	assert.Contains(t, WordsForTheme(ThemeAnimals), RandomWordFromTheme(ThemeAnimals))
+
+	words := UniqueRandomWordsFromTheme(ThemeColors, 40)
+	assert.ElementsMatch(t, WordsForTheme(ThemeColors), words)
+
+	assert.Panics(t, func() { UniqueRandomWordsFromTheme(ThemeColors, 41) })
+}
+
+func TestUniqueRandomWords(t *testing.T) {
+	Init()
+
+	words := UniqueRandomWords(100)
+	assert.Len(t, words, 100)
+
+	uniqueWords := map[string]bool{}
+	for _, word := range words {
+		uniqueWords[word] = true
+	}
+
+	assert.Len(t, uniqueWords, 100)
+}
+
+func TestUniqueRandomStrings(t *testing.T) {
+	Init()
+
+	randomStrings := UniqueRandomStrings(1000)
+
+	uniqueStrings := map[string]bool{}
+	for _, randomString := range randomStrings {
+		assert.Len(t, strings.Split(randomString, " "), 6)
+		uniqueStrings[randomString] = true
+	}
+
+	assert.Len(t, uniqueStrings, 1000)
+}
+
+func TestThemesDontChangeExistingWords(t *testing.T) {
+	os.Setenv("CODECRAFTERS_RANDOM_SEED", "42")
+	defer os.Unsetenv("CODECRAFTERS_RANDOM_SEED")
+	Init()
+
+	assert.Len(t, randomWords, 10)
+	assert.Equal(t, "strawberry pineapple raspberry blueberry banana orange", RandomString())
+}
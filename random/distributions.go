@@ -0,0 +1,158 @@
+package random
+
+import (
+	"math/rand"
+	"time"
+)
+
+func (s *Source) normFloat64() float64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.rng.NormFloat64()
+}
+
+func (s *Source) expFloat64() float64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.rng.ExpFloat64()
+}
+
+// WeightedRandomElementFromArray returns a random element from arr, where arr[i] is chosen with probability
+// weights[i] / sum(weights). It panics if the lengths don't match, or if no weight is positive.
+func WeightedRandomElementFromArray[T any](arr []T, weights []float64) T {
+	return WeightedRandomElementFromArrayWithSource(defaultSource, arr, weights)
+}
+
+// WeightedRandomElementFromArrayWithSource is like WeightedRandomElementFromArray, but uses the given Source.
+func WeightedRandomElementFromArrayWithSource[T any](s *Source, arr []T, weights []float64) T {
+	if len(arr) != len(weights) {
+		panic("arr and weights must have the same length")
+	}
+
+	totalWeight := 0.0
+	for _, weight := range weights {
+		if weight < 0 {
+			panic("weights can't be negative")
+		}
+
+		totalWeight += weight
+	}
+
+	if totalWeight <= 0 {
+		panic("at least one weight must be positive")
+	}
+
+	target := s.float64() * totalWeight
+
+	// Fall back to the last element with a positive weight, in case of floating point rounding
+	chosenIndex := -1
+	for i, weight := range weights {
+		if weight == 0 {
+			continue
+		}
+
+		chosenIndex = i
+		if target < weight {
+			break
+		}
+
+		target -= weight
+	}
+
+	return arr[chosenIndex]
+}
+
+// RandomZipfInt returns an integer in [0, n) following a Zipf distribution: 0 is the most likely value, 1 the second
+// most likely etc. Higher exponents make the distribution more skewed, exponent must be > 1.
+//
+// This is useful for generating "hot keys", i.e. keys[RandomZipfInt(len(keys), 1.1)].
+func RandomZipfInt(n int, exponent float64) int {
+	return defaultSource.RandomZipfInt(n, exponent)
+}
+
+// RandomZipfInt returns an integer in [0, n) following a Zipf distribution: 0 is the most likely value, 1 the second
+// most likely etc. Higher exponents make the distribution more skewed, exponent must be > 1.
+func (s *Source) RandomZipfInt(n int, exponent float64) int {
+	if n <= 0 {
+		panic("n must be positive")
+	}
+
+	if exponent <= 1 {
+		panic("exponent must be greater than 1")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return int(rand.NewZipf(s.rng, exponent, 1, uint64(n-1)).Uint64())
+}
+
+// RandomNormalFloat64 returns a normally distributed float64 with the given mean and standard deviation.
+func RandomNormalFloat64(mean, standardDeviation float64) float64 {
+	return defaultSource.RandomNormalFloat64(mean, standardDeviation)
+}
+
+// RandomNormalFloat64 returns a normally distributed float64 with the given mean and standard deviation.
+func (s *Source) RandomNormalFloat64(mean, standardDeviation float64) float64 {
+	return s.normFloat64()*standardDeviation + mean
+}
+
+// RandomExponentialFloat64 returns an exponentially distributed float64 with the given mean.
+func RandomExponentialFloat64(mean float64) float64 {
+	return defaultSource.RandomExponentialFloat64(mean)
+}
+
+// RandomExponentialFloat64 returns an exponentially distributed float64 with the given mean.
+func (s *Source) RandomExponentialFloat64(mean float64) float64 {
+	return s.expFloat64() * mean
+}
+
+// RandomExponentialDuration returns an exponentially distributed duration with the given mean, like the delay between
+// requests arriving at a server.
+func RandomExponentialDuration(mean time.Duration) time.Duration {
+	return defaultSource.RandomExponentialDuration(mean)
+}
+
+// RandomExponentialDuration returns an exponentially distributed duration with the given mean, like the delay between
+// requests arriving at a server.
+func (s *Source) RandomExponentialDuration(mean time.Duration) time.Duration {
+	return time.Duration(s.RandomExponentialFloat64(float64(mean)))
+}
+
+// SampleInts returns `count` unique random integers between [min, max), in random order.
+//
+// Unlike RandomInts, this takes O(count) time regardless of how close count is to the size of the range, so it's
+// suitable for sampling from large ranges. It panics if count is greater than the range of possible values.
+func SampleInts(min, max int, count int) []int {
+	return defaultSource.SampleInts(min, max, count)
+}
+
+// SampleInts returns `count` unique random integers between [min, max), in random order.
+//
+// Unlike RandomInts, this takes O(count) time regardless of how close count is to the size of the range, so it's
+// suitable for sampling from large ranges. It panics if count is greater than the range of possible values.
+func (s *Source) SampleInts(min, max int, count int) []int {
+	if count > max-min {
+		panic("can't generate more unique random integers than the range of possible values")
+	}
+
+	// Robert Floyd's algorithm: for each j in the last `count` values of the range, pick a random value in [min, j]
+	// and take j instead if that's already taken. Every subset is equally likely.
+	sample := make([]int, 0, count)
+	taken := make(map[int]bool, count)
+
+	for j := max - count; j < max; j++ {
+		value := s.RandomInt(min, j+1)
+		if taken[value] {
+			value = j
+		}
+
+		taken[value] = true
+		sample = append(sample, value)
+	}
+
+	// Floyd's algorithm picks a uniformly random subset, but its order isn't uniformly random
+	return ShuffleArrayWithSource(s, sample)
+}
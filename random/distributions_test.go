@@ -0,0 +1,143 @@
+package random
+
+import (
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeightedRandomElementFromArray(t *testing.T) {
+	t.Setenv("CODECRAFTERS_RANDOM_SEED", "42")
+	Init()
+
+	counts := map[string]int{}
+	for range 10000 {
+		counts[WeightedRandomElementFromArray([]string{"hot", "cold", "never"}, []float64{9, 1, 0})]++
+	}
+
+	assert.Equal(t, 0, counts["never"])
+	assert.InDelta(t, 9000, counts["hot"], 300)
+	assert.InDelta(t, 1000, counts["cold"], 300)
+
+	assert.Panics(t, func() { WeightedRandomElementFromArray([]string{"a"}, []float64{1, 2}) })
+	assert.Panics(t, func() { WeightedRandomElementFromArray([]string{"a"}, []float64{0}) })
+	assert.Panics(t, func() { WeightedRandomElementFromArray([]string{"a", "b"}, []float64{-1, 2}) })
+}
+
+func TestRandomZipfInt(t *testing.T) {
+	t.Setenv("CODECRAFTERS_RANDOM_SEED", "42")
+	Init()
+
+	counts := make([]int, 100)
+	for range 10000 {
+		value := RandomZipfInt(100, 1.5)
+		assert.GreaterOrEqual(t, value, 0)
+		assert.Less(t, value, 100)
+
+		counts[value]++
+	}
+
+	// Lower values should be much more frequent
+	assert.Greater(t, counts[0], counts[1])
+	assert.Greater(t, counts[1], counts[10])
+	assert.Greater(t, counts[0], 10000/3)
+
+	assert.Panics(t, func() { RandomZipfInt(100, 1) })
+	assert.Panics(t, func() { RandomZipfInt(0, 1.5) })
+}
+
+func TestRandomNormalFloat64(t *testing.T) {
+	t.Setenv("CODECRAFTERS_RANDOM_SEED", "42")
+	Init()
+
+	values := make([]float64, 10000)
+	for i := range values {
+		values[i] = RandomNormalFloat64(50, 10)
+	}
+
+	mean, standardDeviation := meanAndStandardDeviation(values)
+	assert.InDelta(t, 50, mean, 0.5)
+	assert.InDelta(t, 10, standardDeviation, 0.5)
+}
+
+func TestRandomExponential(t *testing.T) {
+	t.Setenv("CODECRAFTERS_RANDOM_SEED", "42")
+	Init()
+
+	values := make([]float64, 10000)
+	for i := range values {
+		values[i] = RandomExponentialFloat64(20)
+		assert.GreaterOrEqual(t, values[i], 0.0)
+	}
+
+	mean, _ := meanAndStandardDeviation(values)
+	assert.InDelta(t, 20, mean, 1)
+
+	assert.GreaterOrEqual(t, RandomExponentialDuration(100*time.Millisecond), time.Duration(0))
+}
+
+func TestSampleInts(t *testing.T) {
+	t.Setenv("CODECRAFTERS_RANDOM_SEED", "42")
+	Init()
+
+	t.Run("returns unique values within the range", func(t *testing.T) {
+		sample := SampleInts(1_000_000, 1_000_000_000, 1000)
+		assert.Len(t, sample, 1000)
+
+		seen := map[int]bool{}
+		for _, value := range sample {
+			assert.GreaterOrEqual(t, value, 1_000_000)
+			assert.Less(t, value, 1_000_000_000)
+			assert.False(t, seen[value])
+			seen[value] = true
+		}
+	})
+
+	t.Run("returns all possible values when count equals the range", func(t *testing.T) {
+		assert.ElementsMatch(t, []int{5, 6, 7, 8, 9}, SampleInts(5, 10, 5))
+	})
+
+	t.Run("panics if count is greater than the range", func(t *testing.T) {
+		assert.PanicsWithValue(t, "can't generate more unique random integers than the range of possible values", func() {
+			SampleInts(1, 5, 5)
+		})
+	})
+}
+
+func TestSeededDistributions(t *testing.T) {
+	os.Setenv("CODECRAFTERS_RANDOM_SEED", "42")
+	defer os.Unsetenv("CODECRAFTERS_RANDOM_SEED")
+
+	generate := func() []interface{} {
+		Init()
+
+		return []interface{}{
+			WeightedRandomElementFromArray([]int{1, 2, 3}, []float64{1, 2, 3}),
+			RandomZipfInt(1000, 1.2),
+			RandomNormalFloat64(0, 1),
+			RandomExponentialFloat64(1),
+			SampleInts(0, 1_000_000, 10),
+		}
+	}
+
+	assert.Equal(t, generate(), generate())
+}
+
+func meanAndStandardDeviation(values []float64) (float64, float64) {
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+
+	mean := sum / float64(len(values))
+
+	squaredDifferenceSum := 0.0
+	for _, value := range values {
+		squaredDifferenceSum += (value - mean) * (value - mean)
+	}
+
+	return mean, math.Sqrt(squaredDifferenceSum / float64(len(values)))
+}
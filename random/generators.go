@@ -0,0 +1,232 @@
+package random
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	AlphanumericCharset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	LowercaseCharset    = "abcdefghijklmnopqrstuvwxyz"
+	HexCharset          = "0123456789abcdef"
+
+	// IdentifierCharset is safe for keys & names in most protocols (Redis keys, SQL identifiers, HTTP paths etc.)
+	IdentifierCharset = "abcdefghijklmnopqrstuvwxyz0123456789_"
+)
+
+var fileExtensions = []string{".txt", ".md", ".json", ".log", ".csv", ".go", ".py", ".rs"}
+
+func (s *Source) int63n(n int64) int64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.rng.Int63n(n)
+}
+
+func (s *Source) read(p []byte) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.rng.Read(p)
+}
+
+// RandomStringFromCharset returns a random string of `length` characters from charset.
+func RandomStringFromCharset(charset string, length int) string {
+	return defaultSource.RandomStringFromCharset(charset, length)
+}
+
+// RandomStringFromCharset returns a random string of `length` characters from charset.
+func (s *Source) RandomStringFromCharset(charset string, length int) string {
+	characters := []rune(charset)
+
+	var builder strings.Builder
+	for range length {
+		builder.WriteRune(characters[s.intn(len(characters))])
+	}
+
+	return builder.String()
+}
+
+// RandomAlphanumericString returns a random string of `length` letters and digits.
+func RandomAlphanumericString(length int) string {
+	return defaultSource.RandomAlphanumericString(length)
+}
+
+// RandomAlphanumericString returns a random string of `length` letters and digits.
+func (s *Source) RandomAlphanumericString(length int) string {
+	return s.RandomStringFromCharset(AlphanumericCharset, length)
+}
+
+// RandomHexString returns a random string of `length` lowercase hex digits.
+func RandomHexString(length int) string {
+	return defaultSource.RandomHexString(length)
+}
+
+// RandomHexString returns a random string of `length` lowercase hex digits.
+func (s *Source) RandomHexString(length int) string {
+	return s.RandomStringFromCharset(HexCharset, length)
+}
+
+// RandomIdentifier returns a random identifier of `length` characters that starts with a lowercase letter, followed by
+// characters from IdentifierCharset.
+func RandomIdentifier(length int) string {
+	return defaultSource.RandomIdentifier(length)
+}
+
+// RandomIdentifier returns a random identifier of `length` characters that starts with a lowercase letter, followed by
+// characters from IdentifierCharset.
+func (s *Source) RandomIdentifier(length int) string {
+	if length <= 0 {
+		return ""
+	}
+
+	return s.RandomStringFromCharset(LowercaseCharset, 1) + s.RandomStringFromCharset(IdentifierCharset, length-1)
+}
+
+// RandomBytes returns `length` random bytes.
+func RandomBytes(length int) []byte {
+	return defaultSource.RandomBytes(length)
+}
+
+// RandomBytes returns `length` random bytes.
+func (s *Source) RandomBytes(length int) []byte {
+	bytes := make([]byte, length)
+	s.read(bytes)
+
+	return bytes
+}
+
+// RandomUUID returns a random (version 4) UUID, like "f47ac10b-58cc-4372-a567-0e02b2c3d479".
+func RandomUUID() string {
+	return defaultSource.RandomUUID()
+}
+
+// RandomUUID returns a random (version 4) UUID, like "f47ac10b-58cc-4372-a567-0e02b2c3d479".
+func (s *Source) RandomUUID() string {
+	bytes := s.RandomBytes(16)
+
+	bytes[6] = (bytes[6] & 0x0f) | 0x40 // Version 4
+	bytes[8] = (bytes[8] & 0x3f) | 0x80 // RFC 4122 variant
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", bytes[0:4], bytes[4:6], bytes[6:8], bytes[8:10], bytes[10:16])
+}
+
+// RandomIPv4 returns a random unicast IPv4 address, like "203.0.113.7". Addresses in 0.0.0.0/8, 127.0.0.0/8 and
+// 224.0.0.0/3 (multicast & reserved) are never returned.
+func RandomIPv4() string {
+	return defaultSource.RandomIPv4()
+}
+
+// RandomIPv4 returns a random unicast IPv4 address, like "203.0.113.7". Addresses in 0.0.0.0/8, 127.0.0.0/8 and
+// 224.0.0.0/3 (multicast & reserved) are never returned.
+func (s *Source) RandomIPv4() string {
+	firstOctet := s.RandomInt(1, 223)
+	if firstOctet >= 127 {
+		firstOctet++ // Skip loopback
+	}
+
+	return fmt.Sprintf("%d.%d.%d.%d", firstOctet, s.intn(256), s.intn(256), s.intn(256))
+}
+
+// RandomIPv6 returns a random global unicast (2000::/3) IPv6 address, like "2a03:2880:f12f:83:face:b00c:0:25de".
+func RandomIPv6() string {
+	return defaultSource.RandomIPv6()
+}
+
+// RandomIPv6 returns a random global unicast (2000::/3) IPv6 address, like "2a03:2880:f12f:83:face:b00c:0:25de".
+func (s *Source) RandomIPv6() string {
+	ip := net.IP(s.RandomBytes(16))
+	ip[0] = (ip[0] & 0x1f) | 0x20
+
+	return ip.String()
+}
+
+// RandomPort returns a random port in [10000, 32768). This avoids well-known ports and Linux's default ephemeral
+// port range, so it's unlikely to be in use already.
+func RandomPort() int {
+	return defaultSource.RandomPort()
+}
+
+// RandomPort returns a random port in [10000, 32768). This avoids well-known ports and Linux's default ephemeral
+// port range, so it's unlikely to be in use already.
+func (s *Source) RandomPort() int {
+	return s.RandomInt(10000, 32768)
+}
+
+// RandomTimestamp returns a random time between [start, end).
+func RandomTimestamp(start, end time.Time) time.Time {
+	return defaultSource.RandomTimestamp(start, end)
+}
+
+// RandomTimestamp returns a random time between [start, end).
+func (s *Source) RandomTimestamp(start, end time.Time) time.Time {
+	if !end.After(start) {
+		panic("end must be after start")
+	}
+
+	return start.Add(time.Duration(s.int63n(int64(end.Sub(start)))))
+}
+
+// RandomFilePath returns a random relative file path with `depth` components, like "mango/apple/banana.json".
+func RandomFilePath(depth int) string {
+	return defaultSource.RandomFilePath(depth)
+}
+
+// RandomFilePath returns a random relative file path with `depth` components, like "mango/apple/banana.json".
+func (s *Source) RandomFilePath(depth int) string {
+	if depth <= 0 {
+		panic("depth must be at least 1")
+	}
+
+	components := s.RandomWords(depth)
+	components[depth-1] += RandomElementFromArrayWithSource(s, fileExtensions)
+
+	return strings.Join(components, "/")
+}
+
+// RandomJSONObject returns a random JSON object (to be encoded with encoding/json) that nests objects and arrays up
+// to `maxDepth` levels deep. Values are strings, integers, booleans or nulls.
+func RandomJSONObject(maxDepth int) map[string]interface{} {
+	return defaultSource.RandomJSONObject(maxDepth)
+}
+
+// RandomJSONObject returns a random JSON object (to be encoded with encoding/json) that nests objects and arrays up
+// to `maxDepth` levels deep. Values are strings, integers, booleans or nulls.
+func (s *Source) RandomJSONObject(maxDepth int) map[string]interface{} {
+	object := map[string]interface{}{}
+
+	for _, key := range s.RandomWords(s.RandomInt(1, 5)) {
+		object[key] = s.randomJSONValue(maxDepth - 1)
+	}
+
+	return object
+}
+
+func (s *Source) randomJSONValue(remainingDepth int) interface{} {
+	valueTypeCount := 4
+	if remainingDepth > 0 {
+		valueTypeCount = 6 // Also allow objects & arrays
+	}
+
+	switch s.intn(valueTypeCount) {
+	case 0:
+		return s.RandomWord()
+	case 1:
+		return s.RandomInt(-1000, 1000)
+	case 2:
+		return s.intn(2) == 0
+	case 3:
+		return nil
+	case 4:
+		return s.RandomJSONObject(remainingDepth)
+	default:
+		array := make([]interface{}, s.RandomInt(0, 4))
+		for i := range array {
+			array[i] = s.randomJSONValue(remainingDepth - 1)
+		}
+
+		return array
+	}
+}
@@ -0,0 +1,150 @@
+package random
+
+import (
+	"encoding/json"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStringGenerators(t *testing.T) {
+	Init()
+
+	assert.Regexp(t, regexp.MustCompile(`^[a-zA-Z0-9]{12}$`), RandomAlphanumericString(12))
+	assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{40}$`), RandomHexString(40))
+	assert.Regexp(t, regexp.MustCompile(`^[xyz]{5}$`), RandomStringFromCharset("xyz", 5))
+	assert.Equal(t, "", RandomAlphanumericString(0))
+
+	for range 100 {
+		assert.Regexp(t, regexp.MustCompile(`^[a-z][a-z0-9_]{7}$`), RandomIdentifier(8))
+	}
+}
+
+func TestRandomBytes(t *testing.T) {
+	Init()
+
+	assert.Len(t, RandomBytes(0), 0)
+	assert.Len(t, RandomBytes(100), 100)
+	assert.NotEqual(t, RandomBytes(16), RandomBytes(16))
+}
+
+func TestRandomUUID(t *testing.T) {
+	Init()
+
+	for range 100 {
+		assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), RandomUUID())
+	}
+}
+
+func TestRandomIPAddresses(t *testing.T) {
+	Init()
+
+	for range 1000 {
+		ipv4 := net.ParseIP(RandomIPv4())
+		if assert.NotNil(t, ipv4) && assert.NotNil(t, ipv4.To4()) {
+			assert.True(t, ipv4.IsGlobalUnicast() || ipv4.IsPrivate(), ipv4.String())
+			assert.False(t, ipv4.IsLoopback(), ipv4.String())
+		}
+
+		ipv6 := net.ParseIP(RandomIPv6())
+		if assert.NotNil(t, ipv6) {
+			assert.Nil(t, ipv6.To4())
+			assert.Equal(t, byte(0x20), ipv6[0]&0xe0)
+		}
+	}
+}
+
+func TestRandomPort(t *testing.T) {
+	Init()
+
+	for range 1000 {
+		port := RandomPort()
+		assert.GreaterOrEqual(t, port, 10000)
+		assert.Less(t, port, 32768)
+	}
+}
+
+func TestRandomTimestamp(t *testing.T) {
+	Init()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+
+	for range 1000 {
+		timestamp := RandomTimestamp(start, end)
+		assert.False(t, timestamp.Before(start))
+		assert.True(t, timestamp.Before(end))
+	}
+
+	assert.Panics(t, func() { RandomTimestamp(end, start) })
+}
+
+func TestRandomFilePath(t *testing.T) {
+	Init()
+
+	path := RandomFilePath(3)
+	components := strings.Split(path, "/")
+
+	assert.Len(t, components, 3)
+	assert.Contains(t, randomWords, components[0])
+	assert.Contains(t, randomWords, components[1])
+	assert.Regexp(t, regexp.MustCompile(`^[a-z]+\.[a-z]+$`), components[2])
+}
+
+func TestRandomJSONObject(t *testing.T) {
+	Init()
+
+	var depth func(value interface{}) int
+	depth = func(value interface{}) int {
+		maxChildDepth := 0
+
+		switch value := value.(type) {
+		case map[string]interface{}:
+			for _, child := range value {
+				maxChildDepth = max(maxChildDepth, depth(child))
+			}
+		case []interface{}:
+			for _, child := range value {
+				maxChildDepth = max(maxChildDepth, depth(child))
+			}
+		default:
+			return 0
+		}
+
+		return maxChildDepth + 1
+	}
+
+	for range 100 {
+		object := RandomJSONObject(3)
+		assert.NotEmpty(t, object)
+		assert.LessOrEqual(t, depth(object), 3)
+
+		_, err := json.Marshal(object)
+		assert.NoError(t, err)
+	}
+}
+
+func TestSeededGenerators(t *testing.T) {
+	os.Setenv("CODECRAFTERS_RANDOM_SEED", "42")
+	defer os.Unsetenv("CODECRAFTERS_RANDOM_SEED")
+
+	generate := func() []interface{} {
+		Init()
+
+		return []interface{}{
+			RandomAlphanumericString(8),
+			RandomUUID(),
+			RandomIPv4(),
+			RandomIPv6(),
+			RandomFilePath(2),
+			RandomJSONObject(2),
+		}
+	}
+
+	assert.Equal(t, generate(), generate())
+}
@@ -1,17 +1,33 @@
 package random
 
 import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
 	"math/big"
 	"math/rand"
 	"os"
-	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
-// rng is our package-level random number generator
-var rng *rand.Rand
+// Source is a random number generator. The package-level functions use a default Source created by Init.
+//
+// A Source is safe for concurrent use. Values generated concurrently are only deterministic if the order of calls is.
+type Source struct {
+	// mutex guards rng, rand.Rand isn't safe for concurrent use
+	mutex sync.Mutex
+	rng   *rand.Rand
+}
+
+// defaultSource is used by all package-level functions. It's replaced by Init, but has a value before that so that
+// package-level functions don't panic if Init isn't called (see tester_utils.RunCLIWithOutput).
+var defaultSource = NewSource(time.Now().UnixNano())
+
+// seed is the seed that defaultSource was created with, used to derive per-test-case sources
+var seed int64
 
 var randomWords = []string{
 	"apple",
@@ -28,41 +44,120 @@ var randomWords = []string{
 
 // Init must be called at the start of every program.
 //
-// If CODECRAFTERS_RANDOM_SEED is set, it will be used to generate predictable random numbers.
-func Init() {
-	var source rand.Source
-	if seed := os.Getenv("CODECRAFTERS_RANDOM_SEED"); seed != "" {
-		seedInt, err := strconv.Atoi(seed)
-		if err != nil {
-			panic(err)
-		}
-		source = rand.NewSource(int64(seedInt))
-	} else {
-		source = rand.NewSource(time.Now().UnixNano())
+// If CODECRAFTERS_RANDOM_SEED is set, it will be used to generate predictable random numbers. Otherwise, a seed is
+// chosen based on the current time. Either way, Seed returns the seed in use, so that a run can be reproduced.
+func Init() error {
+	chosenSeed, err := ChooseSeed()
+	if err != nil {
+		return err
+	}
+
+	seed = chosenSeed
+	defaultSource = NewSource(seed)
+
+	return nil
+}
+
+// ChooseSeed returns the seed from CODECRAFTERS_RANDOM_SEED, or one based on the current time if it isn't set.
+//
+// Unlike Init, this doesn't modify any package state, so it's safe to call concurrently.
+func ChooseSeed() (int64, error) {
+	seedFromEnv := os.Getenv("CODECRAFTERS_RANDOM_SEED")
+	if seedFromEnv == "" {
+		return time.Now().UnixNano(), nil
+	}
+
+	seedInt, err := strconv.ParseInt(seedFromEnv, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("CODECRAFTERS_RANDOM_SEED must be an integer, got %q", seedFromEnv)
 	}
 
-	rng = rand.New(source)
+	return seedInt, nil
+}
+
+// Seed returns the seed chosen by Init. Setting CODECRAFTERS_RANDOM_SEED to this value reproduces the same random values.
+func Seed() int64 {
+	return seed
+}
+
+// NewSource returns a Source seeded with seed.
+func NewSource(seed int64) *Source {
+	return &Source{rng: rand.New(rand.NewSource(seed))}
+}
+
+// NewTestCaseSource returns a Source derived from the seed chosen in Init and the test case's slug.
+//
+// Values generated in one test case don't affect values generated in others, so adding a random call to one stage
+// doesn't change the values (and fixtures) of later stages.
+func NewTestCaseSource(slug string) *Source {
+	return NewTestCaseSourceWithSeed(seed, slug)
+}
+
+// NewTestCaseSourceWithSeed is like NewTestCaseSource, but derives the Source from seed instead of the seed chosen in
+// Init.
+func NewTestCaseSourceWithSeed(seed int64, slug string) *Source {
+	hash := fnv.New64a()
+	binary.Write(hash, binary.LittleEndian, seed)
+	hash.Write([]byte(slug))
+
+	return NewSource(int64(hash.Sum64()))
+}
+
+func (s *Source) intn(n int) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.rng.Intn(n)
+}
+
+func (s *Source) float64() float64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.rng.Float64()
+}
+
+func (s *Source) perm(n int) []int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.rng.Perm(n)
 }
 
 // RandomInt returns a random integer between [min, max).
 func RandomInt(min, max int) int {
-	return rng.Intn(max-min) + min
+	return defaultSource.RandomInt(min, max)
+}
+
+// RandomInt returns a random integer between [min, max).
+func (s *Source) RandomInt(min, max int) int {
+	return s.intn(max-min) + min
 }
 
 // RandomInts returns an array of `count` unique random integers between [min, max).
 // It panics if count is greater than the range of possible values.
 func RandomInts(min, max int, count int) []int {
+	return defaultSource.RandomInts(min, max, count)
+}
+
+// RandomInts returns an array of `count` unique random integers between [min, max).
+// It panics if count is greater than the range of possible values. SampleInts is faster when count is close to the
+// size of the range.
+func (s *Source) RandomInts(min, max int, count int) []int {
 	randomInts := []int{}
 
 	if count > max-min {
 		panic("can't generate more unique random integers than the range of possible values")
 	}
 
+	seen := make(map[int]bool, count)
+
 	for range count {
-		randomInt := RandomInt(min, max)
-		for slices.Contains(randomInts, randomInt) {
-			randomInt = RandomInt(min, max)
+		randomInt := s.RandomInt(min, max)
+		for seen[randomInt] {
+			randomInt = s.RandomInt(min, max)
 		}
+		seen[randomInt] = true
 		randomInts = append(randomInts, randomInt)
 	}
 
@@ -71,12 +166,17 @@ func RandomInts(min, max int, count int) []int {
 
 // RandomFloat64 returns a random float64 number between [min, max)
 func RandomFloat64(min, max float64) float64 {
+	return defaultSource.RandomFloat64(min, max)
+}
+
+// RandomFloat64 returns a random float64 number between [min, max)
+func (s *Source) RandomFloat64(min, max float64) float64 {
 	if max < min {
 		panic("max boundary is less than min boundary")
 	}
 
 	// Generate random value in [0, 1) using big.Rat
-	randomInZeroToOne := new(big.Rat).SetFloat64(rng.Float64())
+	randomInZeroToOne := new(big.Rat).SetFloat64(s.float64())
 
 	// Convert min and max to rational numbers
 	bigMin := new(big.Rat).SetFloat64(min)
@@ -107,59 +207,104 @@ func RandomFloat64(min, max float64) float64 {
 
 // RandomFloat64s returns an array of `count` random Float64 values between [min, max).
 func RandomFloat64s(min, max float64, count int) []float64 {
+	return defaultSource.RandomFloat64s(min, max, count)
+}
+
+// RandomFloat64s returns an array of `count` random Float64 values between [min, max).
+func (s *Source) RandomFloat64s(min, max float64, count int) []float64 {
 	randomFloats := make([]float64, count)
 	for i := range count {
-		randomFloats[i] = RandomFloat64(min, max)
+		randomFloats[i] = s.RandomFloat64(min, max)
 	}
 	return randomFloats
 }
 
 // RandomWord returns a random word from the list of words.
 func RandomWord() string {
-	return randomWords[rng.Intn(len(randomWords))]
+	return defaultSource.RandomWord()
+}
+
+// RandomWord returns a random word from the list of words.
+func (s *Source) RandomWord() string {
+	return randomWords[s.intn(len(randomWords))]
 }
 
 // RandomWords returns a random list of n words.
 func RandomWords(n int) []string {
-	return RandomElementsFromArray(randomWords, n)
+	return defaultSource.RandomWords(n)
+}
+
+// RandomWords returns a random list of n words.
+func (s *Source) RandomWords(n int) []string {
+	return RandomElementsFromArrayWithSource(s, randomWords, n)
 }
 
 // RandomString returns a random string of 6 words.
 func RandomString() string {
-	return strings.Join(RandomWords(6), " ")
+	return defaultSource.RandomString()
+}
+
+// RandomString returns a random string of 6 words.
+func (s *Source) RandomString() string {
+	return strings.Join(s.RandomWords(6), " ")
 }
 
 // RandomStrings returns a random list of n strings.
 func RandomStrings(n int) []string {
+	return defaultSource.RandomStrings(n)
+}
+
+// RandomStrings returns a random list of n strings.
+func (s *Source) RandomStrings(n int) []string {
 	l := make([]string, n)
 
 	for i := range l {
-		l[i] = RandomString()
+		l[i] = s.RandomString()
 	}
 
 	return l
 }
 
 func RandomElementFromArray[T any](arr []T) T {
-	return RandomElementsFromArray(arr, 1)[0]
+	return RandomElementFromArrayWithSource(defaultSource, arr)
+}
+
+// RandomElementFromArrayWithSource is like RandomElementFromArray, but uses the given Source (methods can't have type
+// parameters in Go).
+func RandomElementFromArrayWithSource[T any](s *Source, arr []T) T {
+	return RandomElementsFromArrayWithSource(s, arr, 1)[0]
 }
 
 func RandomElementsFromArray[T any](arr []T, count int) []T {
+	return RandomElementsFromArrayWithSource(defaultSource, arr, count)
+}
+
+// RandomElementsFromArrayWithSource is like RandomElementsFromArray, but uses the given Source.
+func RandomElementsFromArrayWithSource[T any](s *Source, arr []T, count int) []T {
 	// Randomly selects `count` unique elements from the given array
 	// and returns them in a new array.
-	for count > len(arr) {
+	// Copied so that appending below never writes into spare capacity of the caller's array
+	pool := make([]T, len(arr))
+	copy(pool, arr)
+
+	for count > len(pool) {
 		// If we need more elements than the array has, we'll just append the array to itself repeatedly.
-		arr = append(arr, arr...)
+		pool = append(pool, pool...)
 	}
 	elements := make([]T, count)
-	indices := rng.Perm(len(arr))[:count]
+	indices := s.perm(len(pool))[:count]
 	for i, randIndex := range indices {
-		elements[i] = arr[randIndex]
+		elements[i] = pool[randIndex]
 	}
 
 	return elements
 }
 
 func ShuffleArray[T any](arr []T) []T {
-	return RandomElementsFromArray(arr, len(arr))
+	return ShuffleArrayWithSource(defaultSource, arr)
+}
+
+// ShuffleArrayWithSource is like ShuffleArray, but uses the given Source.
+func ShuffleArrayWithSource[T any](s *Source, arr []T) []T {
+	return RandomElementsFromArrayWithSource(s, arr, len(arr))
 }
@@ -3,7 +3,9 @@ package random
 import (
 	"math"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -30,6 +32,30 @@ func TestInit(t *testing.T) {
 		assert.Equal(t, val2, RandomInt(0, 100))
 	})
 
+	t.Run("returns an error for a malformed seed", func(t *testing.T) {
+		os.Setenv("CODECRAFTERS_RANDOM_SEED", "not-a-number")
+		defer os.Unsetenv("CODECRAFTERS_RANDOM_SEED")
+
+		assert.EqualError(t, Init(), `CODECRAFTERS_RANDOM_SEED must be an integer, got "not-a-number"`)
+	})
+
+	t.Run("records the chosen seed", func(t *testing.T) {
+		os.Setenv("CODECRAFTERS_RANDOM_SEED", "42")
+		assert.NoError(t, Init())
+		assert.Equal(t, int64(42), Seed())
+
+		os.Unsetenv("CODECRAFTERS_RANDOM_SEED")
+		assert.NoError(t, Init())
+		values := RandomInts(0, 1000000, 5)
+
+		// Reusing the chosen seed reproduces the same values
+		os.Setenv("CODECRAFTERS_RANDOM_SEED", strconv.FormatInt(Seed(), 10))
+		defer os.Unsetenv("CODECRAFTERS_RANDOM_SEED")
+
+		assert.NoError(t, Init())
+		assert.Equal(t, values, RandomInts(0, 1000000, 5))
+	})
+
 	t.Run("works without seed environment variable", func(t *testing.T) {
 		os.Unsetenv("CODECRAFTERS_RANDOM_SEED")
 		Init()
@@ -292,6 +318,15 @@ func TestRandomElementsFromArray(t *testing.T) {
 
 		assert.Len(t, uniqueElements, 5, "should select unique elements when array is large enough")
 	})
+
+	t.Run("does not modify the caller's array", func(t *testing.T) {
+		backingArray := []int{1, 2, 3, 0, 0, 0}
+		array := backingArray[:3]
+		elements := RandomElementsFromArray(array, 5)
+		assert.Len(t, elements, 5)
+
+		assert.Equal(t, []int{1, 2, 3, 0, 0, 0}, backingArray)
+	})
 }
 
 func TestSeededRandomInt(t *testing.T) {
@@ -373,3 +408,75 @@ func TestShuffleArray(t *testing.T) {
 		assert.Equal(t, shuffled1, shuffled2, "same seed should produce same shuffle")
 	})
 }
+
+func TestNewTestCaseSource(t *testing.T) {
+	os.Setenv("CODECRAFTERS_RANDOM_SEED", "42")
+	defer os.Unsetenv("CODECRAFTERS_RANDOM_SEED")
+	Init()
+
+	t.Run("is deterministic for the same seed and slug", func(t *testing.T) {
+		assert.Equal(t, NewTestCaseSource("ab1").RandomInts(0, 1000, 5), NewTestCaseSource("ab1").RandomInts(0, 1000, 5))
+	})
+
+	t.Run("differs between slugs", func(t *testing.T) {
+		assert.NotEqual(t, NewTestCaseSource("ab1").RandomInts(0, 1000000, 5), NewTestCaseSource("ab2").RandomInts(0, 1000000, 5))
+	})
+
+	t.Run("differs between seeds", func(t *testing.T) {
+		values := NewTestCaseSource("ab1").RandomInts(0, 1000000, 5)
+
+		os.Setenv("CODECRAFTERS_RANDOM_SEED", "43")
+		Init()
+
+		assert.NotEqual(t, values, NewTestCaseSource("ab1").RandomInts(0, 1000000, 5))
+	})
+
+	t.Run("is independent of the default source", func(t *testing.T) {
+		Init()
+		values := NewTestCaseSource("ab1").RandomInts(0, 1000000, 5)
+
+		Init()
+		RandomInt(0, 100)
+		assert.Equal(t, values, NewTestCaseSource("ab1").RandomInts(0, 1000000, 5))
+	})
+}
+
+func TestSourceMatchesPackageFunctions(t *testing.T) {
+	os.Setenv("CODECRAFTERS_RANDOM_SEED", "42")
+	defer os.Unsetenv("CODECRAFTERS_RANDOM_SEED")
+	Init()
+
+	source := NewSource(42)
+
+	assert.Equal(t, RandomString(), source.RandomString())
+	assert.Equal(t, ShuffleArray([]int{1, 2, 3, 4, 5}), ShuffleArrayWithSource(source, []int{1, 2, 3, 4, 5}))
+	assert.Equal(t, RandomElementFromArray([]string{"a", "b", "c"}), RandomElementFromArrayWithSource(source, []string{"a", "b", "c"}))
+}
+
+// Run with -race to catch unsynchronized access to the underlying generator
+func TestConcurrentUse(t *testing.T) {
+	os.Setenv("CODECRAFTERS_RANDOM_SEED", "42")
+	defer os.Unsetenv("CODECRAFTERS_RANDOM_SEED")
+	Init()
+
+	var waitGroup sync.WaitGroup
+
+	for range 8 {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			for range 200 {
+				value := RandomInt(0, 10)
+				assert.GreaterOrEqual(t, value, 0)
+				assert.Less(t, value, 10)
+
+				assert.Len(t, RandomWords(3), 3)
+				assert.ElementsMatch(t, []int{1, 2, 3, 4}, ShuffleArray([]int{1, 2, 3, 4}))
+			}
+		}()
+	}
+
+	waitGroup.Wait()
+}
@@ -0,0 +1,258 @@
+package structured_diff_visualizer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/make-core/tester-utils/bytes_diff_visualizer"
+	"gopkg.in/yaml.v2"
+)
+
+// maxDifferenceCount limits how many differences are listed, so that a completely different document doesn't flood the logs
+const maxDifferenceCount = 50
+
+// maxValueLength limits how long an encoded value can be before it is truncated
+const maxValueLength = 80
+
+// VisualizeJSONDiff parses actual and expected as JSON and lists the differences between them by path, returning
+// lines to be presented to the user. For example:
+//
+// > $.users[2].name: expected "x", got "y"
+// > $.users[2].email: missing key (expected "x@example.com")
+// > $.count: unexpected key (got 3)
+//
+// Object key order and whitespace are ignored. If either side isn't valid JSON, this falls back to
+// bytes_diff_visualizer.VisualizeByteDiff.
+//
+// If both are equal, an empty slice is returned.
+func VisualizeJSONDiff(actual []byte, expected []byte) []string {
+	return visualizeStructuredDiff(actual, expected, "JSON", parseJSON)
+}
+
+// VisualizeYAMLDiff is like VisualizeJSONDiff, but parses actual and expected as YAML.
+func VisualizeYAMLDiff(actual []byte, expected []byte) []string {
+	return visualizeStructuredDiff(actual, expected, "YAML", parseYAML)
+}
+
+func visualizeStructuredDiff(actual []byte, expected []byte, formatName string, parse func([]byte) (interface{}, error)) []string {
+	expectedValue, err := parse(expected)
+	if err != nil {
+		return fallbackToByteDiff(actual, expected, fmt.Sprintf("Expected value is not valid %s (%v), showing byte diff instead.", formatName, err))
+	}
+
+	actualValue, err := parse(actual)
+	if err != nil {
+		return fallbackToByteDiff(actual, expected, fmt.Sprintf("Received value is not valid %s (%v), showing byte diff instead.", formatName, err))
+	}
+
+	differences := []string{}
+	compareValues("$", actualValue, expectedValue, &differences)
+
+	if len(differences) > maxDifferenceCount {
+		remainingCount := len(differences) - maxDifferenceCount
+		differences = append(differences[:maxDifferenceCount], fmt.Sprintf("... and %d more differences", remainingCount))
+	}
+
+	return differences
+}
+
+func fallbackToByteDiff(actual []byte, expected []byte, explanation string) []string {
+	byteDiffLines := bytes_diff_visualizer.VisualizeByteDiff(actual, expected)
+	if len(byteDiffLines) == 0 {
+		return byteDiffLines
+	}
+
+	return append([]string{explanation, ""}, byteDiffLines...)
+}
+
+func compareValues(path string, actual interface{}, expected interface{}, differences *[]string) {
+	switch expected := expected.(type) {
+	case map[string]interface{}:
+		if actual, ok := actual.(map[string]interface{}); ok {
+			compareObjects(path, actual, expected, differences)
+			return
+		}
+	case []interface{}:
+		if actual, ok := actual.([]interface{}); ok {
+			compareArrays(path, actual, expected, differences)
+			return
+		}
+	case json.Number:
+		if actual, ok := actual.(json.Number); ok && numbersAreEqual(actual, expected) {
+			return
+		}
+	default:
+		if actual == expected {
+			return
+		}
+	}
+
+	*differences = append(*differences, fmt.Sprintf("%s: expected %s, got %s", path, formatExpected(expected), formatActual(actual)))
+}
+
+func compareObjects(path string, actual map[string]interface{}, expected map[string]interface{}, differences *[]string) {
+	for _, key := range sortedKeys(expected) {
+		keyPath := path + formatKey(key)
+
+		actualValue, ok := actual[key]
+		if !ok {
+			*differences = append(*differences, fmt.Sprintf("%s: missing key (expected %s)", keyPath, formatExpected(expected[key])))
+			continue
+		}
+
+		compareValues(keyPath, actualValue, expected[key], differences)
+	}
+
+	for _, key := range sortedKeys(actual) {
+		if _, ok := expected[key]; !ok {
+			*differences = append(*differences, fmt.Sprintf("%s: unexpected key (got %s)", path+formatKey(key), formatActual(actual[key])))
+		}
+	}
+}
+
+func compareArrays(path string, actual []interface{}, expected []interface{}, differences *[]string) {
+	if len(actual) != len(expected) {
+		*differences = append(*differences, fmt.Sprintf("%s: expected %s, got %d", path, pluralize(len(expected), "element", "elements"), len(actual)))
+	}
+
+	for i := 0; i < max(len(actual), len(expected)); i++ {
+		elementPath := fmt.Sprintf("%s[%d]", path, i)
+
+		switch {
+		case i >= len(actual):
+			*differences = append(*differences, fmt.Sprintf("%s: missing element (expected %s)", elementPath, formatExpected(expected[i])))
+		case i >= len(expected):
+			*differences = append(*differences, fmt.Sprintf("%s: unexpected element (got %s)", elementPath, formatActual(actual[i])))
+		default:
+			compareValues(elementPath, actual[i], expected[i], differences)
+		}
+	}
+}
+
+// numbersAreEqual compares numbers by value, so that 1, 1.0 and 1e0 are considered equal
+func numbersAreEqual(a json.Number, b json.Number) bool {
+	if a == b {
+		return true
+	}
+
+	aRat, aOk := new(big.Rat).SetString(string(a))
+	bRat, bOk := new(big.Rat).SetString(string(b))
+
+	return aOk && bOk && aRat.Cmp(bRat) == 0
+}
+
+func pluralize(count int, singular string, plural string) string {
+	if count == 1 {
+		return fmt.Sprintf("%d %s", count, singular)
+	}
+
+	return fmt.Sprintf("%d %s", count, plural)
+}
+
+var identifierRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func formatKey(key string) string {
+	if identifierRegex.MatchString(key) {
+		return "." + key
+	}
+
+	encodedKey, _ := json.Marshal(key)
+	return fmt.Sprintf("[%s]", encodedKey)
+}
+
+func formatExpected(value interface{}) string {
+	return color.New(color.FgHiGreen).Sprint(formatValue(value))
+}
+
+func formatActual(value interface{}) string {
+	return color.New(color.FgHiRed).Sprint(formatValue(value))
+}
+
+func formatValue(value interface{}) string {
+	buffer := bytes.NewBuffer([]byte{})
+
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+
+	encoded := strings.TrimSuffix(buffer.String(), "\n")
+	if len(encoded) > maxValueLength {
+		return encoded[:maxValueLength] + "..."
+	}
+
+	return encoded
+}
+
+func sortedKeys(value map[string]interface{}) []string {
+	keys := make([]string, 0, len(value))
+	for key := range value {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+func parseJSON(value []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(value))
+	decoder.UseNumber()
+
+	var parsed interface{}
+	if err := decoder.Decode(&parsed); err != nil {
+		return nil, err
+	}
+
+	// Decoding another value is the only way to catch stray data like the "}" in "{}}", decoder.More() doesn't
+	if err := decoder.Decode(&struct{}{}); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after top-level value")
+	}
+
+	return parsed, nil
+}
+
+func parseYAML(value []byte) (interface{}, error) {
+	var parsed interface{}
+	if err := yaml.Unmarshal(value, &parsed); err != nil {
+		return nil, err
+	}
+
+	return normalizeYAMLValue(parsed), nil
+}
+
+// normalizeYAMLValue converts values decoded by yaml.v2 into the same shapes that parseJSON returns
+func normalizeYAMLValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[interface{}]interface{}:
+		normalized := make(map[string]interface{}, len(value))
+		for key, element := range value {
+			normalized[fmt.Sprintf("%v", key)] = normalizeYAMLValue(element)
+		}
+
+		return normalized
+	case []interface{}:
+		normalized := make([]interface{}, len(value))
+		for i, element := range value {
+			normalized[i] = normalizeYAMLValue(element)
+		}
+
+		return normalized
+	case int:
+		return json.Number(fmt.Sprintf("%d", value))
+	case uint64:
+		return json.Number(fmt.Sprintf("%d", value))
+	case float64:
+		return json.Number(fmt.Sprintf("%v", value))
+	default:
+		return value
+	}
+}
@@ -0,0 +1,96 @@
+package structured_diff_visualizer
+
+import (
+	"os"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	color.NoColor = true
+
+	os.Exit(m.Run())
+}
+
+func TestVisualizeJSONDiffIgnoresKeyOrderAndWhitespace(t *testing.T) {
+	actual := []byte(`{"b": [1, 2.0], "a": "x"}`)
+	expected := []byte("{\n  \"a\": \"x\",\n  \"b\": [1, 2]\n}")
+
+	assert.Equal(t, []string{}, VisualizeJSONDiff(actual, expected))
+}
+
+func TestVisualizeJSONDiffReportsPaths(t *testing.T) {
+	actual := []byte(`{
+		"users": [{"name": "a"}, {"name": "b"}, {"name": "y", "admin": true}],
+		"count": "3",
+		"page size": 10
+	}`)
+	expected := []byte(`{
+		"users": [{"name": "a"}, {"name": "b"}, {"name": "x", "email": "x@example.com"}],
+		"count": 3,
+		"next": null
+	}`)
+
+	assert.Equal(t, []string{
+		`$.count: expected 3, got "3"`,
+		`$.next: missing key (expected null)`,
+		`$.users[2].email: missing key (expected "x@example.com")`,
+		`$.users[2].name: expected "x", got "y"`,
+		`$.users[2].admin: unexpected key (got true)`,
+		`$["page size"]: unexpected key (got 10)`,
+	}, VisualizeJSONDiff(actual, expected))
+}
+
+func TestVisualizeJSONDiffReportsArrayLengths(t *testing.T) {
+	assert.Equal(t, []string{
+		`$: expected 3 elements, got 2`,
+		`$[1]: expected {"a":1}, got 5`,
+		`$[2]: missing element (expected 3)`,
+	}, VisualizeJSONDiff([]byte(`[1, 5]`), []byte(`[1, {"a": 1}, 3]`)))
+}
+
+func TestVisualizeJSONDiffFallsBackToByteDiff(t *testing.T) {
+	lines := VisualizeJSONDiff([]byte(`{"a": 1`), []byte(`{"a": 1}`))
+
+	assert.Equal(t, "Received value is not valid JSON (unexpected EOF), showing byte diff instead.", lines[0])
+	assert.Equal(t, "", lines[1])
+	assert.Equal(t, "Expected (bytes 0-8), hexadecimal:                          | ASCII:", lines[2])
+
+	lines = VisualizeJSONDiff([]byte(`{}`), []byte(`{} {}`))
+	assert.Equal(t, "Expected value is not valid JSON (unexpected data after top-level value), showing byte diff instead.", lines[0])
+
+	lines = VisualizeJSONDiff([]byte(`{}}`), []byte(`{}`))
+	assert.Equal(t, "Received value is not valid JSON (unexpected data after top-level value), showing byte diff instead.", lines[0])
+}
+
+func TestVisualizeJSONDiffLimitsDifferenceCount(t *testing.T) {
+	actual := []byte{'['}
+	expected := []byte{'['}
+	for i := 0; i < 60; i++ {
+		if i > 0 {
+			actual = append(actual, ',')
+			expected = append(expected, ',')
+		}
+
+		actual = append(actual, '1')
+		expected = append(expected, '2')
+	}
+	actual = append(actual, ']')
+	expected = append(expected, ']')
+
+	lines := VisualizeJSONDiff(actual, expected)
+	assert.Len(t, lines, maxDifferenceCount+1)
+	assert.Equal(t, "... and 10 more differences", lines[maxDifferenceCount])
+}
+
+func TestVisualizeYAMLDiff(t *testing.T) {
+	actual := []byte("name: redis\nports:\n  - 6379\n  - 6380\n")
+	expected := []byte("ports: [6379]\nname: redis\n")
+
+	assert.Equal(t, []string{
+		`$.ports: expected 1 element, got 2`,
+		`$.ports[1]: unexpected element (got 6380)`,
+	}, VisualizeYAMLDiff(actual, expected))
+}
@@ -3,6 +3,7 @@ package test_case_harness
 import (
 	"github.com/make-core/tester-utils/executable"
 	"github.com/make-core/tester-utils/logger"
+	"github.com/make-core/tester-utils/random"
 )
 
 // TestCaseHarness is passed to your TestCase's TestFunc.
@@ -28,6 +29,10 @@ type TestCaseHarness struct {
 	// Executable is the program to be tested.
 	Executable *executable.Executable
 
+	// Random is a random number generator specific to this test case (see random.NewTestCaseSource). Values generated
+	// with it don't change when other test cases add or remove random calls.
+	Random *random.Source
+
 	// teardownFuncs are run once the error has been reported to the user
 	teardownFuncs []func()
 }
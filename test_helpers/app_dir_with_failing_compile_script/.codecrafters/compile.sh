@@ -0,0 +1,3 @@
+#!/bin/sh
+echo "main.c:1:1: error: expected declaration" >&2
+exit 1
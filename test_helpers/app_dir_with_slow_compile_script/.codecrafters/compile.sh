@@ -0,0 +1,5 @@
+#!/bin/sh
+echo "compiling main.c"
+sleep 0.1
+echo "main.c:1:1: warning: unused variable" >&2
+exec sleep 10
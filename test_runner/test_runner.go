@@ -6,6 +6,7 @@ import (
 
 	"github.com/make-core/tester-utils/executable"
 	"github.com/make-core/tester-utils/logger"
+	"github.com/make-core/tester-utils/random"
 	"github.com/make-core/tester-utils/test_case_harness"
 	"github.com/make-core/tester-utils/tester_definition"
 )
@@ -25,31 +26,68 @@ type TestRunnerStep struct {
 type TestRunner struct {
 	isQuiet bool // Used for anti-cheat tests, where we only want Critical logs to be emitted
 	steps   []TestRunnerStep
+
+	// LoggerOptions are used for all loggers created by the runner. Loggers that relay the program's output should use
+	// these too, so that the program's output is nested in the test case's log groups.
+	LoggerOptions logger.Options
+
+	// RandomSeed is used to derive each step's random source, see random.NewTestCaseSourceWithSeed. Defaults to the seed
+	// chosen in random.Init.
+	RandomSeed int64
+
+	// ShouldBufferStepLogs holds each step's logs in memory, and only prints them if the step fails. Passing steps only
+	// emit a one-line summary and their hints.
+	//
+	// Buffering applies to the sinks in LoggerOptions, so these shouldn't be shared with other runners.
+	ShouldBufferStepLogs bool
 }
 
 func NewTestRunner(steps []TestRunnerStep) TestRunner {
 	return TestRunner{
-		steps: steps,
+		steps:         steps,
+		LoggerOptions: newRunnerLoggerOptions(),
+		RandomSeed:    random.Seed(),
 	}
 }
 
 func NewQuietTestRunner(steps []TestRunnerStep) TestRunner {
-	return TestRunner{isQuiet: true, steps: steps}
+	return TestRunner{
+		isQuiet:       true,
+		steps:         steps,
+		LoggerOptions: newRunnerLoggerOptions(),
+		RandomSeed:    random.Seed(),
+	}
+}
+
+// newRunnerLoggerOptions returns options that write to logger.StdoutSink using a sink that can be buffered without
+// affecting other runners
+func newRunnerLoggerOptions() logger.Options {
+	return logger.Options{
+		Sinks:     []*logger.Sink{logger.NewSink(logger.StdoutSink)},
+		LogGroups: logger.NewLogGroups(),
+	}
 }
 
 // Run runs all tests in a stageRunner
 func (r TestRunner) Run(isDebug bool, executable *executable.Executable) bool {
 	for index, step := range r.steps {
-		if index != 0 {
-			fmt.Println("")
-		}
-
 		testCaseHarness := test_case_harness.TestCaseHarness{
 			Logger:     r.getLoggerForStep(isDebug, step),
 			Executable: executable.Clone(),
+			Random:     random.NewTestCaseSourceWithSeed(r.RandomSeed, step.TestCase.Slug),
 		}
 
 		logger := testCaseHarness.Logger
+
+		if index != 0 {
+			logger.PrintBlankLine()
+		}
+
+		if r.ShouldBufferStepLogs {
+			r.startBufferingLogs()
+		}
+
+		r.LoggerOptions.Stopwatch.Reset()
 		logger.Infof("Running tests for %s", step.Title)
 
 		stepResultChannel := make(chan error, 1)
@@ -69,9 +107,15 @@ func (r TestRunner) Run(isDebug bool, executable *executable.Executable) bool {
 		}
 
 		if err != nil {
+			r.stopBufferingLogs(true) // Show full details for the failing step
 			r.reportTestError(err, isDebug, logger)
 		} else {
-			logger.Successf("Test passed.")
+			// Groups are shared by all steps, so ones left open shouldn't swallow the next step's logs
+			logger.EndAllGroups()
+
+			if !r.ShouldBufferStepLogs {
+				logger.Successf("Test passed.")
+			}
 		}
 
 		testCaseHarness.RunTeardownFuncs()
@@ -79,6 +123,14 @@ func (r TestRunner) Run(isDebug bool, executable *executable.Executable) bool {
 		if err != nil {
 			return false
 		}
+
+		if r.ShouldBufferStepLogs {
+			r.stopBufferingLogs(false)
+
+			// Hints are useful even if the step passed, so they aren't discarded with the rest of the logs
+			logger.ReplayHints()
+			logger.Successf("%s passed.", step.Title)
+		}
 	}
 
 	return true
@@ -86,9 +138,21 @@ func (r TestRunner) Run(isDebug bool, executable *executable.Executable) bool {
 
 func (r TestRunner) getLoggerForStep(isDebug bool, step TestRunnerStep) *logger.Logger {
 	if r.isQuiet {
-		return logger.GetQuietLogger("")
+		return logger.GetQuietLoggerWithOptions("", r.LoggerOptions)
 	} else {
-		return logger.GetLogger(isDebug, fmt.Sprintf("[%s] ", step.TesterLogPrefix))
+		return logger.GetLoggerWithOptions(isDebug, fmt.Sprintf("[%s] ", step.TesterLogPrefix), r.LoggerOptions)
+	}
+}
+
+func (r TestRunner) startBufferingLogs() {
+	for _, sink := range r.LoggerOptions.GetSinks() {
+		sink.StartBuffering()
+	}
+}
+
+func (r TestRunner) stopBufferingLogs(shouldFlush bool) {
+	for _, sink := range r.LoggerOptions.GetSinks() {
+		sink.StopBuffering(shouldFlush)
 	}
 }
 
@@ -101,6 +165,9 @@ func (r TestRunner) reportTestError(err error, isDebug bool, logger *logger.Logg
 		logger.Errorf("Test failed " +
 			"(try setting 'debug: true' in your codecrafters.yml to see more details)")
 	}
+
+	// Hints might've been emitted much earlier in the logs, let's make sure they're visible
+	logger.ReplayHints()
 }
 
 // Fuck you, go
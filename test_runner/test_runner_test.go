@@ -0,0 +1,205 @@
+package test_runner
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/make-core/tester-utils/executable"
+	"github.com/make-core/tester-utils/logger"
+	"github.com/make-core/tester-utils/stdio_mocker"
+	"github.com/make-core/tester-utils/test_case_harness"
+	"github.com/make-core/tester-utils/tester_definition"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	color.NoColor = true
+
+	os.Exit(m.Run())
+}
+
+func buildStep(slug string, testFunc func(*test_case_harness.TestCaseHarness) error) TestRunnerStep {
+	return TestRunnerStep{
+		TestCase:        tester_definition.TestCase{Slug: slug, TestFunc: testFunc},
+		TesterLogPrefix: slug,
+		Title:           "Stage " + slug,
+	}
+}
+
+func TestBufferedStepLogsAreOnlyShownOnFailure(t *testing.T) {
+	buffer := bytes.NewBuffer([]byte{})
+
+	logVerbosely := func(harness *test_case_harness.TestCaseHarness) error {
+		harness.Logger.Infof("detail")
+		return nil
+	}
+
+	runner := NewTestRunner([]TestRunnerStep{
+		buildStep("test-1", logVerbosely),
+		buildStep("test-2", func(harness *test_case_harness.TestCaseHarness) error {
+			harness.Logger.Infof("detail")
+			return errors.New("fail")
+		}),
+	})
+	runner.LoggerOptions = logger.Options{Sinks: []*logger.Sink{logger.NewSink(buffer)}}
+	runner.ShouldBufferStepLogs = true
+
+	assert.False(t, runner.Run(true, executable.NewExecutable("true")))
+
+	expected := strings.Join([]string{
+		"[test-1] Stage test-1 passed.",
+		"",
+		"[test-2] Running tests for Stage test-2",
+		"[test-2] detail",
+		"[test-2] fail",
+		"[test-2] Test failed",
+	}, "\n") + "\n"
+
+	assert.Equal(t, expected, buffer.String())
+}
+
+func TestBufferedStepsKeepHints(t *testing.T) {
+	buffer := bytes.NewBuffer([]byte{})
+
+	runner := NewTestRunner([]TestRunnerStep{
+		buildStep("test-1", func(harness *test_case_harness.TestCaseHarness) error {
+			harness.Logger.Infof("detail")
+			harness.Logger.Hintf("Did you forget \\r\\n?")
+			return nil
+		}),
+	})
+	runner.LoggerOptions = logger.Options{Sinks: []*logger.Sink{logger.NewSink(buffer)}}
+	runner.ShouldBufferStepLogs = true
+
+	assert.True(t, runner.Run(false, executable.NewExecutable("true")))
+
+	expected := strings.Join([]string{
+		"[test-1] Hint: Did you forget \\r\\n?",
+		"[test-1] Stage test-1 passed.",
+	}, "\n") + "\n"
+
+	assert.Equal(t, expected, buffer.String())
+}
+
+func TestBufferingDoesNotAffectOtherRunners(t *testing.T) {
+	m := stdio_mocker.NewStdIOMocker()
+	m.Start()
+	defer m.End()
+
+	bufferedStepStarted := make(chan bool)
+	otherRunnerFinished := make(chan bool)
+
+	bufferedRunner := NewTestRunner([]TestRunnerStep{
+		buildStep("buffered", func(harness *test_case_harness.TestCaseHarness) error {
+			close(bufferedStepStarted)
+			<-otherRunnerFinished
+			return nil
+		}),
+	})
+	bufferedRunner.ShouldBufferStepLogs = true
+
+	otherRunner := NewTestRunner([]TestRunnerStep{
+		buildStep("other", func(harness *test_case_harness.TestCaseHarness) error {
+			harness.Logger.Infof("detail")
+			return nil
+		}),
+	})
+
+	bufferedRunnerResult := make(chan bool)
+	go func() {
+		bufferedRunnerResult <- bufferedRunner.Run(false, executable.NewExecutable("true"))
+	}()
+
+	<-bufferedStepStarted
+	assert.True(t, otherRunner.Run(false, executable.NewExecutable("true")))
+	close(otherRunnerFinished)
+	assert.True(t, <-bufferedRunnerResult)
+
+	m.End()
+	assert.Contains(t, string(m.ReadStdout()), "[other] detail\n")
+	assert.Contains(t, string(m.ReadStdout()), "[buffered] Stage buffered passed.\n")
+}
+
+func TestUnbufferedStepLogs(t *testing.T) {
+	buffer := bytes.NewBuffer([]byte{})
+
+	runner := NewTestRunner([]TestRunnerStep{
+		buildStep("test-1", func(harness *test_case_harness.TestCaseHarness) error {
+			harness.Logger.Infof("detail")
+			return nil
+		}),
+	})
+	runner.LoggerOptions = logger.Options{Sinks: []*logger.Sink{logger.NewSink(buffer)}}
+
+	assert.True(t, runner.Run(true, executable.NewExecutable("true")))
+
+	expected := strings.Join([]string{
+		"[test-1] Running tests for Stage test-1",
+		"[test-1] detail",
+		"[test-1] Test passed.",
+	}, "\n") + "\n"
+
+	assert.Equal(t, expected, buffer.String())
+}
+
+func TestFailureExpandsOpenGroups(t *testing.T) {
+	buffer := bytes.NewBuffer([]byte{})
+
+	runner := NewTestRunner([]TestRunnerStep{
+		buildStep("test-1", func(harness *test_case_harness.TestCaseHarness) error {
+			harness.Logger.BeginGroup("client-1")
+			harness.Logger.Infof("$ redis-cli PING")
+			return errors.New("fail")
+		}),
+	})
+	runner.LoggerOptions = logger.Options{CollapseGroupsWithoutErrors: true, Sinks: []*logger.Sink{logger.NewSink(buffer)}}
+
+	assert.False(t, runner.Run(true, executable.NewExecutable("true")))
+
+	expected := strings.Join([]string{
+		"[test-1] Running tests for Stage test-1",
+		"[test-1] client-1",
+		"[test-1]   $ redis-cli PING",
+		"[test-1]   fail",
+		"[test-1]   Test failed",
+	}, "\n") + "\n"
+
+	assert.Equal(t, expected, buffer.String())
+}
+
+func TestEachStepGetsItsOwnRandomSource(t *testing.T) {
+	generatedValues := map[string][]int{}
+
+	generateValues := func(slug string, count int) func(*test_case_harness.TestCaseHarness) error {
+		return func(harness *test_case_harness.TestCaseHarness) error {
+			for range count {
+				generatedValues[slug] = append(generatedValues[slug], harness.Random.RandomInt(0, 1000000))
+			}
+
+			return nil
+		}
+	}
+
+	runWithFirstStepCount := func(count int) {
+		generatedValues = map[string][]int{}
+
+		runner := NewTestRunner([]TestRunnerStep{
+			buildStep("test-1", generateValues("test-1", count)),
+			buildStep("test-2", generateValues("test-2", 3)),
+		})
+		runner.LoggerOptions = logger.Options{Sinks: []*logger.Sink{logger.NewSink(bytes.NewBuffer([]byte{}))}}
+
+		assert.True(t, runner.Run(false, executable.NewExecutable("true")))
+	}
+
+	runWithFirstStepCount(1)
+	secondStepValues := generatedValues["test-2"]
+
+	// Generating more values in the first step shouldn't affect the second step
+	runWithFirstStepCount(5)
+	assert.Equal(t, secondStepValues, generatedValues["test-2"])
+}
@@ -1,8 +1,14 @@
 package tester_utils
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
 
+	"github.com/make-core/tester-utils/color_policy"
 	"github.com/make-core/tester-utils/executable"
 	"github.com/make-core/tester-utils/internal"
 	"github.com/make-core/tester-utils/logger"
@@ -15,10 +21,22 @@ import (
 type Tester struct {
 	context    tester_context.TesterContext
 	definition tester_definition.TesterDefinition
+
+	// stopwatch is shared by all loggers, and is reset at the start of each stage. Only set if elapsed times are shown.
+	stopwatch *logger.Stopwatch
+
+	// redactor is shared by all loggers, so that values redacted by a test case are also redacted in program output
+	redactor *logger.Redactor
+
+	// sink is where all output is written, defaults to logger.StdoutSink
+	sink *logger.Sink
+
+	// seed is used to derive each test case's random source, see random.NewTestCaseSourceWithSeed
+	seed int64
 }
 
 // newTester creates a Tester based on the TesterDefinition provided
-func newTester(env map[string]string, definition tester_definition.TesterDefinition) (Tester, error) {
+func newTester(env map[string]string, definition tester_definition.TesterDefinition, sink *logger.Sink, seed int64) (Tester, error) {
 	context, err := tester_context.GetTesterContext(env, definition)
 	if err != nil {
 		if userError, ok := err.(*internal.UserError); ok {
@@ -31,6 +49,13 @@ func newTester(env map[string]string, definition tester_definition.TesterDefinit
 	tester := Tester{
 		context:    context,
 		definition: definition,
+		redactor:   logger.NewRedactor(),
+		sink:       sink,
+		seed:       seed,
+	}
+
+	if context.ShouldShowElapsedTime {
+		tester.stopwatch = logger.NewStopwatch()
 	}
 
 	if err := tester.validateContext(); err != nil {
@@ -42,19 +67,63 @@ func newTester(env map[string]string, definition tester_definition.TesterDefinit
 
 // RunCLI executes the tester based on user-provided env vars
 func RunCLI(env map[string]string, definition tester_definition.TesterDefinition) int {
-	random.Init()
+	if err := random.Init(); err != nil {
+		fmt.Println(err.Error())
+		return 1
+	}
 
-	tester, err := newTester(env, definition)
+	tester, err := newTester(env, definition, logger.StdoutSink, random.Seed())
 	if err != nil {
 		fmt.Println(err.Error())
 		return 1
 	}
 
+	// Loggers created by test cases (and other packages like bytes_diff_visualizer) use the global color setting
+	color_policy.Apply(tester.context.ColorPolicy)
+
+	return tester.run()
+}
+
+// RunCLIWithOutput is like RunCLI, but writes all output to output instead of os.Stdout. Useful for capturing output in
+// tests.
+//
+// Unlike RunCLI, this doesn't modify any global state (see random.Init and color_policy.Apply), so it's safe to call
+// concurrently. Test cases should use the harness' Logger and Random instead of package-level functions.
+func RunCLIWithOutput(env map[string]string, definition tester_definition.TesterDefinition, output io.Writer) int {
+	sink := logger.NewSink(output)
+
+	seed, err := random.ChooseSeed()
+	if err != nil {
+		fmt.Fprintln(sink, err.Error())
+		return 1
+	}
+
+	tester, err := newTester(env, definition, sink, seed)
+	if err != nil {
+		fmt.Fprintln(sink, err.Error())
+		return 1
+	}
+
+	return tester.run()
+}
+
+// run runs the compile step and all stages, returning the exit code
+func (tester Tester) run() int {
 	tester.printDebugContext()
 
+	// Structured reports always include the seed, debug mode or not
+	if tester.context.LogFormat == logger.JSONFormat {
+		tester.printRandomSeed()
+	}
+
 	// TODO: Validate context here instead of in NewTester?
 
+	if !tester.runCompileStep() {
+		return 1
+	}
+
 	if !tester.runStages() {
+		tester.printRandomSeedHint()
 		return 1
 	}
 
@@ -71,8 +140,107 @@ func (tester Tester) printDebugContext() {
 		return
 	}
 
-	tester.context.Print()
-	fmt.Println("")
+	// Plain fmt output would break consumers that expect one JSON object per line
+	if tester.context.LogFormat == logger.JSONFormat {
+		logger.GetLoggerWithOptions(true, "", tester.getLoggerOptions()).Debugf("Debug = %v", tester.context.IsDebug)
+		return
+	}
+
+	tester.context.Fprint(tester.sink)
+	tester.printRandomSeed()
+	fmt.Fprintln(tester.sink, "")
+}
+
+// printRandomSeed prints the seed used for random values. This is done in debug mode, and always when logging JSON so
+// that every structured report includes it.
+func (tester Tester) printRandomSeed() {
+	// Seeds differ between runs, they'd break fixtures
+	if internal.IsRecordingOrEvaluatingFixtures {
+		return
+	}
+
+	if tester.context.LogFormat == logger.JSONFormat {
+		logger.GetLoggerWithOptions(true, "", tester.getLoggerOptions()).Debugf("Random seed = %d", tester.seed)
+		return
+	}
+
+	fmt.Fprintln(tester.sink, "Random seed =", tester.seed)
+}
+
+// printRandomSeedHint tells the user how to reproduce a failure that might depend on random values
+func (tester Tester) printRandomSeedHint() {
+	if internal.IsRecordingOrEvaluatingFixtures {
+		return
+	}
+
+	logger.GetLoggerWithOptions(tester.context.IsDebug, "", tester.getLoggerOptions()).Hintf("Rerun with CODECRAFTERS_RANDOM_SEED=%d to reproduce the same random values.", tester.seed)
+}
+
+// runCompileStep runs the compile script (if present) once before any stages are run. Returns true if compilation
+// succeeds or if there's nothing to compile.
+func (tester Tester) runCompileStep() bool {
+	if tester.context.CompileScriptPath == "" {
+		return true
+	}
+
+	logger := logger.GetLoggerWithOptions(tester.context.IsDebug, "[compile] ", tester.getLoggerOptions())
+
+	// WorkingDir is set below, so the script path must not be relative to the current directory
+	compileScriptPath, err := filepath.Abs(tester.context.CompileScriptPath)
+	if err != nil {
+		logger.Errorf("CodeCrafters internal error. Error resolving compile script path: %v", err)
+		return false
+	}
+
+	// Lines from stdout and stderr are collected as they arrive, so that their interleaving is preserved
+	var outputMutex sync.Mutex
+	outputLines := []string{}
+
+	compileExecutable := executable.NewVerboseExecutable(compileScriptPath, func(line string) {
+		outputMutex.Lock()
+		defer outputMutex.Unlock()
+
+		outputLines = append(outputLines, line)
+	})
+
+	compileTimeout := tester.definition.CustomOrDefaultCompileTimeout()
+	compileExecutable.TimeoutInMilliseconds = int(compileTimeout.Milliseconds())
+	compileExecutable.WorkingDir = tester.context.RepositoryDir
+
+	tester.stopwatch.Reset()
+	logger.Debugf("Running %s", tester.definition.CompileScriptFileName)
+
+	result, err := compileExecutable.Run()
+
+	printOutput := func() {
+		outputMutex.Lock()
+		defer outputMutex.Unlock()
+
+		if len(outputLines) > 0 {
+			logger.Plainln(strings.Join(outputLines, "\n"))
+		}
+	}
+
+	if errors.Is(err, executable.ErrTimedOut) {
+		printOutput()
+		logger.Errorf("Compilation timed out, exceeded %d seconds", int64(compileTimeout.Seconds()))
+		return false
+	}
+
+	if err != nil {
+		logger.Errorf("Compilation failed: %s", err)
+		return false
+	}
+
+	if result.ExitCode != 0 {
+		printOutput()
+		logger.Errorf("Compilation failed (exit code %d)", result.ExitCode)
+		return false
+	}
+
+	logger.Debugf("Compilation succeeded")
+
+	return true
 }
 
 // runAntiCheatStages runs any anti-cheat stages specified in the TesterDefinition. Only critical logs are emitted. If
@@ -83,7 +251,10 @@ func (tester Tester) runAntiCheatStages() bool {
 
 // runStages runs all the stages upto the current stage the user is attempting. Returns true if all stages pass.
 func (tester Tester) runStages() bool {
-	return tester.getRunner().Run(tester.context.IsDebug, tester.getExecutable())
+	runner := tester.getRunner()
+
+	// The executable's logs must be buffered along with the runner's, so they share the runner's sinks
+	return runner.Run(tester.context.IsDebug, tester.getExecutable(runner.LoggerOptions))
 }
 
 func (tester Tester) getRunner() test_runner.TestRunner {
@@ -99,7 +270,12 @@ func (tester Tester) getRunner() test_runner.TestRunner {
 		})
 	}
 
-	return test_runner.NewTestRunner(steps)
+	runner := test_runner.NewTestRunner(steps)
+	runner.LoggerOptions = tester.getRunnerLoggerOptions()
+	runner.RandomSeed = tester.seed
+	runner.ShouldBufferStepLogs = tester.context.ShouldBufferStepLogs && !tester.context.IsDebug // Debug mode shows everything
+
+	return runner
 }
 
 func (tester Tester) getAntiCheatRunner() test_runner.TestRunner {
@@ -113,15 +289,43 @@ func (tester Tester) getAntiCheatRunner() test_runner.TestRunner {
 		})
 	}
 
-	return test_runner.NewQuietTestRunner(steps) // We only want Critical logs to be emitted for anti-cheat tests
+	runner := test_runner.NewQuietTestRunner(steps) // We only want Critical logs to be emitted for anti-cheat tests
+	runner.LoggerOptions = tester.getRunnerLoggerOptions()
+	runner.RandomSeed = tester.seed
+
+	return runner
 }
 
 func (tester Tester) getQuietExecutable() *executable.Executable {
 	return executable.NewExecutable(tester.context.ExecutablePath)
 }
 
-func (tester Tester) getExecutable() *executable.Executable {
-	return executable.NewVerboseExecutable(tester.context.ExecutablePath, logger.GetLogger(true, "[your_program] ").Plainln)
+func (tester Tester) getExecutable(loggerOptions logger.Options) *executable.Executable {
+	return executable.NewVerboseExecutable(tester.context.ExecutablePath, logger.GetLoggerWithOptions(true, "[your_program] ", loggerOptions).Plainln)
+}
+
+func (tester Tester) getLoggerOptions() logger.Options {
+	return logger.Options{
+		Format:           tester.context.LogFormat,
+		ColorPolicy:      tester.context.ColorPolicy,
+		Stopwatch:        tester.stopwatch,
+		Sinks:            []*logger.Sink{tester.sink},
+		Redactor:         tester.redactor,
+		DeduplicateHints: true,
+
+		// Debug mode shows everything
+		CollapseGroupsWithoutErrors: tester.context.ShouldCollapseLogGroups && !tester.context.IsDebug,
+	}
+}
+
+// getRunnerLoggerOptions is like getLoggerOptions, but writes to a sink of its own. Runners buffer their sinks (see
+// TestRunner.ShouldBufferStepLogs), this ensures that other output isn't held back or discarded.
+func (tester Tester) getRunnerLoggerOptions() logger.Options {
+	options := tester.getLoggerOptions()
+	options.Sinks = []*logger.Sink{logger.NewSink(tester.sink)}
+	options.LogGroups = logger.NewLogGroups() // Shared by the runner's loggers and the executable's logger
+
+	return options
 }
 
 func (tester Tester) validateContext() error {
@@ -4,10 +4,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path"
 
+	"github.com/make-core/tester-utils/color_policy"
 	"github.com/make-core/tester-utils/internal"
+	"github.com/make-core/tester-utils/logger"
 	"github.com/make-core/tester-utils/tester_definition"
 	"gopkg.in/yaml.v2"
 )
@@ -27,17 +30,30 @@ type TesterContextTestCase struct {
 // TesterContext holds all flags passed in via environment variables, or from the codecrafters.yml file
 type TesterContext struct {
 	ExecutablePath               string
+	RepositoryDir                string
+	CompileScriptPath            string
 	IsDebug                      bool
+	ShouldShowElapsedTime        bool
 	TestCases                    []TesterContextTestCase
 	ShouldSkipAntiCheatTestCases bool
+	ShouldBufferStepLogs         bool
+	ShouldCollapseLogGroups      bool
+	LogFormat                    logger.Format
+	ColorPolicy                  color_policy.ColorPolicy
 }
 
 type yamlConfig struct {
-	Debug bool `yaml:"debug"`
+	Debug           bool `yaml:"debug"`
+	ShowElapsedTime bool `yaml:"show_elapsed_time"`
 }
 
 func (c TesterContext) Print() {
-	fmt.Println("Debug =", c.IsDebug)
+	c.Fprint(os.Stdout)
+}
+
+// Fprint is like Print, but writes to w.
+func (c TesterContext) Fprint(w io.Writer) {
+	fmt.Fprintln(w, "Debug =", c.IsDebug)
 }
 
 // GetContext parses flags and returns a Context object
@@ -64,6 +80,24 @@ func GetTesterContext(env map[string]string, definition tester_definition.Tester
 		shouldSkipAntiCheatTestCases = true
 	}
 
+	shouldBufferStepLogs := env["CODECRAFTERS_BUFFER_STEP_LOGS"] == "true"
+	shouldCollapseLogGroups := env["CODECRAFTERS_COLLAPSE_LOG_GROUPS"] == "true"
+
+	logFormat, err := logger.ParseFormat(env["CODECRAFTERS_LOG_FORMAT"])
+	if err != nil {
+		return TesterContext{}, fmt.Errorf("failed to parse CODECRAFTERS_LOG_FORMAT: %s", err)
+	}
+
+	colorPolicy, err := color_policy.FromEnv(env)
+	if err != nil {
+		return TesterContext{}, fmt.Errorf("failed to parse CODECRAFTERS_COLOR: %s", err)
+	}
+
+	// Escape codes would end up inside JSON messages, so colors are only emitted if explicitly requested
+	if logFormat == logger.JSONFormat && env["CODECRAFTERS_COLOR"] != string(color_policy.Always) {
+		colorPolicy = color_policy.Never
+	}
+
 	for _, testCase := range testCases {
 		if testCase.Slug == "" {
 			return TesterContext{}, fmt.Errorf("CODECRAFTERS_TEST_CASES_JSON contains a test case with an empty slug")
@@ -93,6 +127,17 @@ func GetTesterContext(env map[string]string, definition tester_definition.Tester
 		}
 	}
 
+	compileScriptPath := ""
+
+	if definition.CompileScriptFileName != "" {
+		candidateCompileScriptPath := path.Join(submissionDir, definition.CompileScriptFileName)
+
+		// The compile script is optional, languages that don't need a compile step won't have one
+		if _, err := os.Stat(candidateCompileScriptPath); err == nil {
+			compileScriptPath = candidateCompileScriptPath
+		}
+	}
+
 	configPath := path.Join(submissionDir, "codecrafters.yml")
 
 	yamlConfig, err := readFromYAML(configPath)
@@ -108,9 +153,16 @@ func GetTesterContext(env map[string]string, definition tester_definition.Tester
 
 	return TesterContext{
 		ExecutablePath:               executablePath,
+		RepositoryDir:                submissionDir,
+		CompileScriptPath:            compileScriptPath,
 		IsDebug:                      yamlConfig.Debug,
+		ShouldShowElapsedTime:        yamlConfig.ShowElapsedTime,
 		TestCases:                    testCases,
 		ShouldSkipAntiCheatTestCases: shouldSkipAntiCheatTestCases,
+		ShouldBufferStepLogs:         shouldBufferStepLogs,
+		ShouldCollapseLogGroups:      shouldCollapseLogGroups,
+		LogFormat:                    logFormat,
+		ColorPolicy:                  colorPolicy,
 	}, nil
 }
 
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/make-core/tester-utils/color_policy"
+	"github.com/make-core/tester-utils/logger"
 	"github.com/make-core/tester-utils/tester_definition"
 	"github.com/stretchr/testify/assert"
 )
@@ -67,3 +69,71 @@ func TestCorrectExecutable(t *testing.T) {
 		assert.Equal(t, context.ExecutablePath, fmt.Sprintf("test_helpers/%s/%s", tt.submissionDir, tt.expectedExecutable))
 	}
 }
+
+func TestParsesLogFormat(t *testing.T) {
+	context, err := GetTesterContext(map[string]string{
+		"CODECRAFTERS_TEST_CASES_JSON": `[{ "slug": "test", "tester_log_prefix": "test", "title": "Test"}]`,
+		"CODECRAFTERS_REPOSITORY_DIR":  "./test_helpers/valid_app_dir",
+		"CODECRAFTERS_LOG_FORMAT":      "json",
+	}, tester_definition.TesterDefinition{})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, logger.JSONFormat, context.LogFormat)
+
+	_, err = GetTesterContext(map[string]string{
+		"CODECRAFTERS_TEST_CASES_JSON": `[{ "slug": "test", "tester_log_prefix": "test", "title": "Test"}]`,
+		"CODECRAFTERS_REPOSITORY_DIR":  "./test_helpers/valid_app_dir",
+		"CODECRAFTERS_LOG_FORMAT":      "xml",
+	}, tester_definition.TesterDefinition{})
+	assert.ErrorContains(t, err, "unknown log format")
+}
+
+func TestJSONLogFormatDisablesColorsByDefault(t *testing.T) {
+	context, err := GetTesterContext(map[string]string{
+		"CODECRAFTERS_TEST_CASES_JSON": `[{ "slug": "test", "tester_log_prefix": "test", "title": "Test"}]`,
+		"CODECRAFTERS_REPOSITORY_DIR":  "./test_helpers/valid_app_dir",
+		"CODECRAFTERS_LOG_FORMAT":      "json",
+	}, tester_definition.TesterDefinition{})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, color_policy.Never, context.ColorPolicy)
+
+	context, err = GetTesterContext(map[string]string{
+		"CODECRAFTERS_TEST_CASES_JSON": `[{ "slug": "test", "tester_log_prefix": "test", "title": "Test"}]`,
+		"CODECRAFTERS_REPOSITORY_DIR":  "./test_helpers/valid_app_dir",
+		"CODECRAFTERS_LOG_FORMAT":      "json",
+		"CODECRAFTERS_COLOR":           "always",
+	}, tester_definition.TesterDefinition{})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, color_policy.Always, context.ColorPolicy)
+
+	context, err = GetTesterContext(map[string]string{
+		"CODECRAFTERS_TEST_CASES_JSON": `[{ "slug": "test", "tester_log_prefix": "test", "title": "Test"}]`,
+		"CODECRAFTERS_REPOSITORY_DIR":  "./test_helpers/valid_app_dir",
+	}, tester_definition.TesterDefinition{})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, color_policy.Always, context.ColorPolicy)
+}
+
+func TestParsesCollapseLogGroups(t *testing.T) {
+	context, err := GetTesterContext(map[string]string{
+		"CODECRAFTERS_TEST_CASES_JSON":     `[{ "slug": "test", "tester_log_prefix": "test", "title": "Test"}]`,
+		"CODECRAFTERS_REPOSITORY_DIR":      "./test_helpers/valid_app_dir",
+		"CODECRAFTERS_COLLAPSE_LOG_GROUPS": "true",
+	}, tester_definition.TesterDefinition{})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	assert.True(t, context.ShouldCollapseLogGroups)
+}
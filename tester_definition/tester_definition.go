@@ -35,10 +35,27 @@ type TesterDefinition struct {
 	ExecutableFileName       string
 	LegacyExecutableFileName string
 
+	// CompileScriptFileName is an optional script that'll be run once before any test cases are run. If the script
+	// isn't present in the user's repository, the compile step is skipped.
+	//
+	// Example: .codecrafters/compile.sh
+	CompileScriptFileName string
+
+	// CompileTimeout is the maximum amount of time that the compile script can run for.
+	CompileTimeout time.Duration
+
 	TestCases          []TestCase
 	AntiCheatTestCases []TestCase
 }
 
+func (t TesterDefinition) CustomOrDefaultCompileTimeout() time.Duration {
+	if t.CompileTimeout == 0 {
+		return 60 * time.Second
+	} else {
+		return t.CompileTimeout
+	}
+}
+
 func (t TesterDefinition) TestCaseBySlug(slug string) TestCase {
 	for _, testCase := range t.TestCases {
 		if testCase.Slug == slug {
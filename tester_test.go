@@ -1,10 +1,13 @@
 package tester_utils
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/make-core/tester-utils/test_case_harness"
 	"github.com/make-core/tester-utils/tester_definition"
@@ -64,3 +67,160 @@ func TestOneStageFails(t *testing.T) {
 	exitCode := RunCLI(env, definition)
 	assert.Equal(t, exitCode, 1)
 }
+
+func TestCompileScriptFails(t *testing.T) {
+	tests := []struct {
+		repositoryDir  string
+		compileTimeout time.Duration
+		expectedOutput []string
+	}{
+		{
+			repositoryDir: "./test_helpers/app_dir_with_failing_compile_script",
+			expectedOutput: []string{
+				"[compile] main.c:1:1: error: expected declaration",
+				"[compile] Compilation failed (exit code 1)",
+			},
+		},
+		{
+			repositoryDir:  "./test_helpers/app_dir_with_slow_compile_script",
+			compileTimeout: time.Second,
+			expectedOutput: []string{
+				"[compile] compiling main.c",
+				"[compile] main.c:1:1: warning: unused variable",
+				"[compile] Compilation timed out, exceeded 1 seconds",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		testFuncWasCalled := false
+
+		definition := tester_definition.TesterDefinition{
+			CompileScriptFileName: ".codecrafters/compile.sh",
+			CompileTimeout:        test.compileTimeout,
+			TestCases: []tester_definition.TestCase{
+				{Slug: "test-1", TestFunc: func(harness *test_case_harness.TestCaseHarness) error {
+					testFuncWasCalled = true
+					return nil
+				}},
+			},
+		}
+
+		env := map[string]string{
+			"CODECRAFTERS_REPOSITORY_DIR":  test.repositoryDir,
+			"CODECRAFTERS_TEST_CASES_JSON": buildTestCasesJson([]string{"test-1"}),
+			"CODECRAFTERS_COLOR":           "never",
+		}
+
+		output := bytes.NewBuffer([]byte{})
+		exitCode := RunCLIWithOutput(env, definition, output)
+
+		assert.Equal(t, exitCode, 1)
+		assert.False(t, testFuncWasCalled)
+		assert.Contains(t, output.String(), strings.Join(test.expectedOutput, "\n")+"\n")
+	}
+}
+
+func TestCompileScriptPasses(t *testing.T) {
+	definition := tester_definition.TesterDefinition{
+		CompileScriptFileName: ".codecrafters/compile.sh",
+		TestCases: []tester_definition.TestCase{
+			{Slug: "test-1", TestFunc: passFunc},
+		},
+	}
+
+	env := map[string]string{
+		"CODECRAFTERS_REPOSITORY_DIR":  "./test_helpers/app_dir_with_passing_compile_script",
+		"CODECRAFTERS_TEST_CASES_JSON": buildTestCasesJson([]string{"test-1"}),
+	}
+	exitCode := RunCLI(env, definition)
+	assert.Equal(t, exitCode, 0)
+}
+
+func TestCompileScriptMissing(t *testing.T) {
+	definition := tester_definition.TesterDefinition{
+		CompileScriptFileName: ".codecrafters/compile.sh",
+		TestCases: []tester_definition.TestCase{
+			{Slug: "test-1", TestFunc: passFunc},
+		},
+	}
+
+	env := map[string]string{
+		"CODECRAFTERS_REPOSITORY_DIR":  "./test_helpers/valid_app_dir",
+		"CODECRAFTERS_TEST_CASES_JSON": buildTestCasesJson([]string{"test-1"}),
+	}
+	exitCode := RunCLI(env, definition)
+	assert.Equal(t, exitCode, 0)
+}
+
+func TestMalformedRandomSeed(t *testing.T) {
+	t.Setenv("CODECRAFTERS_RANDOM_SEED", "abc")
+
+	definition := tester_definition.TesterDefinition{
+		TestCases: []tester_definition.TestCase{
+			{Slug: "test-1", TestFunc: passFunc},
+		},
+	}
+
+	env := map[string]string{
+		"CODECRAFTERS_REPOSITORY_DIR":  "./test_helpers/valid_app_dir",
+		"CODECRAFTERS_TEST_CASES_JSON": buildTestCasesJson([]string{"test-1"}),
+	}
+
+	output := bytes.NewBuffer([]byte{})
+	exitCode := RunCLIWithOutput(env, definition, output)
+
+	assert.Equal(t, exitCode, 1)
+	assert.Contains(t, output.String(), `CODECRAFTERS_RANDOM_SEED must be an integer, got "abc"`)
+}
+
+func TestFailureShowsRandomSeed(t *testing.T) {
+	t.Setenv("CODECRAFTERS_RANDOM_SEED", "1234")
+
+	definition := tester_definition.TesterDefinition{
+		TestCases: []tester_definition.TestCase{
+			{Slug: "test-1", TestFunc: failFunc},
+		},
+	}
+
+	env := map[string]string{
+		"CODECRAFTERS_REPOSITORY_DIR":  "./test_helpers/valid_app_dir",
+		"CODECRAFTERS_TEST_CASES_JSON": buildTestCasesJson([]string{"test-1"}),
+	}
+
+	output := bytes.NewBuffer([]byte{})
+	exitCode := RunCLIWithOutput(env, definition, output)
+
+	assert.Equal(t, exitCode, 1)
+	assert.Contains(t, output.String(), "Rerun with CODECRAFTERS_RANDOM_SEED=1234 to reproduce the same random values.")
+}
+
+func TestRunCLIWithOutputInParallel(t *testing.T) {
+	definition := tester_definition.TesterDefinition{
+		TestCases: []tester_definition.TestCase{
+			{Slug: "test-1", TestFunc: func(harness *test_case_harness.TestCaseHarness) error {
+				harness.Logger.Infof("Generated %d", harness.Random.RandomInt(0, 100))
+				return nil
+			}},
+		},
+	}
+
+	for _, colorPolicy := range []string{"always", "never"} {
+		t.Run(colorPolicy, func(t *testing.T) {
+			t.Parallel()
+
+			env := map[string]string{
+				"CODECRAFTERS_REPOSITORY_DIR":  "./test_helpers/valid_app_dir",
+				"CODECRAFTERS_TEST_CASES_JSON": buildTestCasesJson([]string{"test-1"}),
+				"CODECRAFTERS_COLOR":           colorPolicy,
+			}
+
+			output := bytes.NewBuffer([]byte{})
+			exitCode := RunCLIWithOutput(env, definition, output)
+
+			assert.Equal(t, 0, exitCode)
+			assert.Contains(t, output.String(), "Generated ")
+			assert.Equal(t, colorPolicy == "always", strings.Contains(output.String(), "\x1b["))
+		})
+	}
+}
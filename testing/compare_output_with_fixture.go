@@ -2,13 +2,12 @@ package testing
 
 import (
 	"bytes"
-	"io/ioutil"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
-	"github.com/make-core/tester-utils/executable"
+	"github.com/make-core/tester-utils/line_diff_visualizer"
 )
 
 func CompareOutputWithFixture(t *testing.T, testerOutput []byte, normalizeOutputFunc func([]byte) []byte, fixturePath string) {
@@ -41,41 +40,10 @@ func CompareOutputWithFixture(t *testing.T, testerOutput []byte, normalizeOutput
 		return
 	}
 
-	diffExecutablePath, err := exec.LookPath("diff")
-	if err != nil {
-		panic(err)
-	}
-
-	diffExecutable := executable.NewExecutable(diffExecutablePath)
-
-	testerOutputTmpFile, err := ioutil.TempFile("", "")
-	if err != nil {
-		panic(err)
-	}
-
-	if _, err = testerOutputTmpFile.Write(normalizedTesterOutput); err != nil {
-		panic(err)
-	}
-
-	fixtureTmpFile, err := ioutil.TempFile("", "")
-	if err != nil {
-		panic(err)
-	}
-
-	if _, err = fixtureTmpFile.Write(normalizedFixturesContents); err != nil {
-		panic(err)
-	}
-
-	result, err := diffExecutable.Run("-u", fixtureTmpFile.Name(), testerOutputTmpFile.Name())
-	if err != nil {
-		panic(err)
-	}
-
-	// Remove the first two lines of the diff output
-	diffContents := bytes.SplitN(result.Stdout, []byte("\n"), 3)[2]
+	diffLines := line_diff_visualizer.VisualizeLineDiff(string(normalizedTesterOutput), string(normalizedFixturesContents))
 
 	os.Stdout.Write([]byte("\n\nDifferences detected:\n\n"))
-	os.Stdout.Write(diffContents)
+	os.Stdout.Write([]byte(strings.Join(diffLines, "\n")))
 	os.Stdout.Write([]byte("\n\nRe-run this test with CODECRAFTERS_RECORD_FIXTURES=true to update fixtures\n\n"))
 	t.FailNow()
 }
@@ -1,6 +1,6 @@
 package testing
 
-var _isRecordingOrEvaluatingFixtures = false
+import "github.com/make-core/tester-utils/internal"
 
 // IsRecordingOrEvaluatingFixtures can be used to run code required to control any
 // external sources of randomness that can affect fixtures.
@@ -12,5 +12,5 @@ var _isRecordingOrEvaluatingFixtures = false
 // values generated by the tester should use tester_utils.random, which uses a fixed
 // seed across all test runs.
 func IsRecordingOrEvaluatingFixtures() bool {
-	return _isRecordingOrEvaluatingFixtures
+	return internal.IsRecordingOrEvaluatingFixtures
 }
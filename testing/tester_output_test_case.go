@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	tester_utils "github.com/make-core/tester-utils"
+	"github.com/make-core/tester-utils/internal"
 	"github.com/make-core/tester-utils/stdio_mocker"
 	"github.com/make-core/tester-utils/tester_definition"
 	"github.com/stretchr/testify/assert"
@@ -81,14 +82,16 @@ func buildTestCasesJsonUntilStageSlug(untilStageSlug string, testerDefinition te
 }
 
 func TestTesterOutput(t *testing.T, testerDefinition tester_definition.TesterDefinition, testCases map[string]TesterOutputTestCase) {
+	// Output is captured from stdout (instead of using RunCLIWithOutput) so that fixtures include anything that testers
+	// print directly
 	m := stdio_mocker.NewStdIOMocker()
 	defer m.End()
 
 	// Used in testing.IsRecordingOrEvaluatingFixtures()
-	_isRecordingOrEvaluatingFixtures = true
+	internal.IsRecordingOrEvaluatingFixtures = true
 
 	defer func() {
-		_isRecordingOrEvaluatingFixtures = false
+		internal.IsRecordingOrEvaluatingFixtures = false
 	}()
 
 	for testName, testCase := range testCases {